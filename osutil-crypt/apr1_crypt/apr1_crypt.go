@@ -51,3 +51,15 @@ func (c *crypter) Verify(hashedKey string, key []byte) error {
 func (c *crypter) Cost(hashedKey string) (int, error) { return RoundsDefault, nil }
 
 func (c *crypter) SetSalt(salt crypt.Salt) {}
+
+// SetParams only accepts the fixed number of rounds.
+func (c *crypter) SetParams(p crypt.Params) error {
+	if p.Rounds != 0 && p.Rounds != RoundsDefault {
+		return crypt.ErrSaltRounds
+	}
+	return nil
+}
+
+func (c *crypter) Params(hashedKey string) (crypt.Params, error) {
+	return crypt.Params{Rounds: RoundsDefault}, nil
+}
@@ -0,0 +1,243 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+// Package argon2_crypt implements the Argon2id password hashing algorithm, the
+// winner of the Password Hashing Competition.
+//
+// The specification for this algorithm can be found here:
+// https://www.rfc-editor.org/rfc/rfc9106
+//
+// As explained in the package common, the parameters of a hashed key are
+// bounded: the memory by MemoryMax and the work, the memory by the time, by
+// WorkMax, which is a pass over 2 GiB or about 5 seconds of a CPU.
+package argon2_crypt
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"strconv"
+
+	"golang.org/x/crypto/argon2"
+
+	"trident.li/go/osutil-crypt/common"
+)
+
+const (
+	MagicPrefix   = "$argon2id$"
+	SaltLenMin    = 8 // In bytes, before encoding.
+	SaltLenMax    = 16
+	RoundsMin     = 1 // The time parameter, as passes over the memory.
+	RoundsMax     = 1024
+	RoundsDefault = 3
+
+	MemoryMin      = 8 // In KiB; at least 8 times the parallelism.
+	MemoryMax      = 2 * 1024 * 1024
+	WorkMax        = 2 * 1024 * 1024 // In KiB by the time.
+	MemoryDefault  = 64 * 1024
+	ThreadsMin     = 1
+	ThreadsMax     = 255
+	ThreadsDefault = 4
+
+	KeyLen = 32
+)
+
+type crypter struct {
+	Salt crypt.Salt
+
+	memory  uint32
+	threads uint8
+}
+
+// New returns a new crypt.Crypter computing the Argon2id password hashing with
+// the default parameters.
+func New() crypt.Crypter {
+	return NewWithParams(MemoryDefault, RoundsDefault, ThreadsDefault)
+}
+
+// NewWithParams returns a new crypt.Crypter computing the Argon2id password
+// hashing, which uses the given memory (in KiB), time and parallelism when a
+// salt has to be generated.
+func NewWithParams(memory, time uint32, threads uint8) crypt.Crypter {
+	if threads < ThreadsMin {
+		threads = ThreadsMin
+	}
+	if memory < 8*uint32(threads) {
+		memory = 8 * uint32(threads)
+	} else if memory > MemoryMax {
+		memory = MemoryMax
+	}
+	if time < RoundsMin {
+		time = RoundsMin
+	} else if time > RoundsMax {
+		time = RoundsMax
+	}
+	if time > WorkMax/memory {
+		time = WorkMax / memory
+	}
+
+	return &crypter{
+		Salt: crypt.Salt{
+			MagicPrefix:   []byte(MagicPrefix),
+			SaltLenMin:    SaltLenMin,
+			SaltLenMax:    SaltLenMax,
+			RoundsDefault: int(time),
+			RoundsMin:     RoundsMin,
+			RoundsMax:     RoundsMax,
+		},
+		memory:  memory,
+		threads: threads,
+	}
+}
+
+// Generate hashes the key with a salt of the form
+// "$argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>", in the PHC string
+// format. If the salt is followed by a hash, the new hash has its same length.
+func (c *crypter) Generate(key, salt []byte) (string, error) {
+	if len(salt) == 0 {
+		salt = c.generateSalt()
+	}
+	if !bytes.HasPrefix(salt, c.Salt.MagicPrefix) {
+		return "", crypt.ErrSaltPrefix
+	}
+
+	h, err := crypt.ParsePHC(string(salt))
+	if err != nil {
+		return "", err
+	}
+	if h.Version != argon2.Version || h.Salt == nil {
+		return "", crypt.ErrSaltFormat
+	}
+	memory, time, threads, err := parseParams(h)
+	if err != nil {
+		return "", err
+	}
+	if len(h.Salt) < SaltLenMin {
+		return "", crypt.ErrSaltFormat
+	}
+
+	keyLen := uint32(KeyLen)
+	if len(h.Hash) >= 4 {
+		keyLen = uint32(len(h.Hash))
+	}
+
+	h.Hash = argon2.IDKey(key, h.Salt, time, memory, threads, keyLen)
+	out := h.String()
+
+	// Clean sensitive data.
+	for i := range h.Hash {
+		h.Hash[i] = 0
+	}
+
+	return out, nil
+}
+
+func (c *crypter) Verify(hashedKey string, key []byte) error {
+	newHash, err := c.Generate(key, []byte(hashedKey))
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare([]byte(newHash), []byte(hashedKey)) != 1 {
+		return crypt.ErrKeyMismatch
+	}
+	return nil
+}
+
+// Cost returns the time parameter used to create the given hashed key.
+func (c *crypter) Cost(hashedKey string) (int, error) {
+	p, err := c.Params(hashedKey)
+	return p.Rounds, err
+}
+
+func (c *crypter) SetSalt(salt crypt.Salt) { c.Salt = salt }
+
+// SetParams sets the time (as rounds), the memory and the parallelism.
+func (c *crypter) SetParams(p crypt.Params) error {
+	time, memory, threads := c.Salt.RoundsDefault, int64(c.memory), int(c.threads)
+	if p.Rounds != 0 {
+		time = p.Rounds
+	}
+	if p.Memory != 0 {
+		memory = int64(p.Memory)
+	}
+	if p.Parallelism != 0 {
+		threads = p.Parallelism
+	}
+
+	if time < RoundsMin || time > RoundsMax ||
+		threads < ThreadsMin || threads > ThreadsMax ||
+		memory < 8*int64(threads) || memory > MemoryMax ||
+		int64(time)*memory > WorkMax {
+		return crypt.ErrSaltRounds
+	}
+
+	c.Salt.RoundsDefault = time
+	c.memory = uint32(memory)
+	c.threads = uint8(threads)
+	return nil
+}
+
+func (c *crypter) Params(hashedKey string) (crypt.Params, error) {
+	h, err := crypt.ParsePHC(hashedKey)
+	if err != nil {
+		return crypt.Params{}, err
+	}
+	memory, time, threads, err := parseParams(h)
+	if err != nil {
+		return crypt.Params{}, err
+	}
+	return crypt.Params{
+		Rounds:      int(time),
+		Memory:      int(memory),
+		Parallelism: int(threads),
+	}, nil
+}
+
+// generateSalt returns a random salt using the parameters of the crypter.
+func (c *crypter) generateSalt() []byte {
+	h := &crypt.PHC{
+		ID:      string(bytes.Trim(c.Salt.MagicPrefix, "$")),
+		Version: argon2.Version,
+		Params:  formatParams(c.memory, uint32(c.Salt.RoundsDefault), c.threads),
+		Salt:    make([]byte, c.Salt.SaltLenMax),
+	}
+	rand.Read(h.Salt)
+	return []byte(h.String())
+}
+
+// parseParams parses the parameters "m=<memory>,t=<time>,p=<threads>".
+func parseParams(h *crypt.PHC) (memory, time uint32, threads uint8, err error) {
+	if len(h.Params) != 3 ||
+		h.Params[0].Name != "m" || h.Params[1].Name != "t" || h.Params[2].Name != "p" {
+		return 0, 0, 0, crypt.ErrSaltFormat
+	}
+
+	m, err := strconv.ParseUint(h.Params[0].Value, 10, 32)
+	if err != nil {
+		return 0, 0, 0, crypt.ErrSaltFormat
+	}
+	t, err := strconv.ParseUint(h.Params[1].Value, 10, 32)
+	if err != nil || t < RoundsMin || t > RoundsMax {
+		return 0, 0, 0, crypt.ErrSaltRounds
+	}
+	p, err := strconv.ParseUint(h.Params[2].Value, 10, 8)
+	if err != nil || p < ThreadsMin || m < 8*p {
+		return 0, 0, 0, crypt.ErrSaltFormat
+	}
+	if m > MemoryMax || m*t > WorkMax {
+		return 0, 0, 0, crypt.ErrSaltRounds
+	}
+
+	return uint32(m), uint32(t), uint8(p), nil
+}
+
+func formatParams(memory, time uint32, threads uint8) []crypt.PHCParam {
+	return []crypt.PHCParam{
+		{Name: "m", Value: strconv.FormatUint(uint64(memory), 10)},
+		{Name: "t", Value: strconv.FormatUint(uint64(time), 10)},
+		{Name: "p", Value: strconv.FormatUint(uint64(threads), 10)},
+	}
+}
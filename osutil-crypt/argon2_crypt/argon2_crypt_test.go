@@ -0,0 +1,143 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package argon2_crypt
+
+import (
+	"testing"
+
+	"trident.li/go/osutil-crypt/common"
+)
+
+var argon2Crypt = New()
+
+func TestGenerate(t *testing.T) {
+	data := []struct {
+		salt []byte
+		key  []byte
+		out  string
+		cost int
+	}{
+		{
+			[]byte("$argon2id$v=19$m=65536,t=2,p=4$c29tZXNhbHQ"),
+			[]byte("password"),
+			"$argon2id$v=19$m=65536,t=2,p=4$c29tZXNhbHQ$" +
+				"GpZ3sK/oH9p7VIiV56G/64Zo/8GaUw434IimaPqxwCo",
+			2,
+		},
+		{
+			[]byte("$argon2id$v=19$m=4096,t=3,p=1$c29tZXNhbHQ$" +
+				"qLml5cbqFAO6YxVHhrSBHP0UWdxrIxkNcM8aMX3blzU"),
+			[]byte("password"),
+			"$argon2id$v=19$m=4096,t=3,p=1$c29tZXNhbHQ$" +
+				"qLml5cbqFAO6YxVHhrSBHP0UWdxrIxkNcM8aMX3blzU",
+			3,
+		},
+	}
+
+	for i, d := range data {
+		hash, err := argon2Crypt.Generate(d.key, d.salt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hash != d.out {
+			t.Errorf("Test %d failed\nExpected: %s, got: %s", i, d.out, hash)
+		}
+
+		cost, err := argon2Crypt.Cost(hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cost != d.cost {
+			t.Errorf("Test %d failed\nExpected: %d, got: %d", i, d.cost, cost)
+		}
+	}
+}
+
+func TestGenerateParams(t *testing.T) {
+	hash, err := NewWithParams(1024, 2, 1).Generate([]byte("password"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash[:len("$argon2id$v=19$m=1024,t=2,p=1$")] != "$argon2id$v=19$m=1024,t=2,p=1$" {
+		t.Errorf("Expected parameters m=1024,t=2,p=1, got: %s", hash)
+	}
+
+	if _, err = argon2Crypt.Generate([]byte("password"),
+		[]byte("$argon2id$v=16$m=4096,t=3,p=1$c29tZXNhbHQ")); err == nil {
+		t.Error("Expected error for unsupported version")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	data := [][]byte{
+		[]byte("password"),
+		[]byte("12345"),
+		[]byte("That's amazing! I've got the same combination on my luggage!"),
+		[]byte("And change the combination on my luggage!"),
+		[]byte("         random  spa  c    ing."),
+		[]byte("94ajflkvjzpe8u3&*j1k513KLJ&*()"),
+	}
+	for i, d := range data {
+		hash, err := argon2Crypt.Generate(d, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = argon2Crypt.Verify(hash, d); err != nil {
+			t.Errorf("Test %d failed: %s", i, d)
+		}
+	}
+}
+
+func TestParams(t *testing.T) {
+	c := New()
+	want := crypt.Params{Rounds: 2, Memory: 1024, Parallelism: 2}
+
+	if err := c.SetParams(crypt.Params{Memory: 8, Parallelism: 2}); err != crypt.ErrSaltRounds {
+		t.Errorf("Expected ErrSaltRounds, got: %v", err)
+	}
+	if err := c.SetParams(crypt.Params{Memory: MemoryMax + 1}); err != crypt.ErrSaltRounds {
+		t.Errorf("Expected ErrSaltRounds, got: %v", err)
+	}
+	if err := c.SetParams(crypt.Params{Rounds: RoundsMax + 1}); err != crypt.ErrSaltRounds {
+		t.Errorf("Expected ErrSaltRounds, got: %v", err)
+	}
+	if err := c.SetParams(crypt.Params{Rounds: 2, Memory: MemoryMax}); err != crypt.ErrSaltRounds {
+		t.Errorf("Expected ErrSaltRounds, got: %v", err)
+	}
+	if err := c.SetParams(want); err != nil {
+		t.Fatal(err)
+	}
+
+	hash, err := c.Generate([]byte("password"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params, err := c.Params(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params != want {
+		t.Errorf("Expected: %+v, got: %+v", want, params)
+	}
+	if err = c.Verify(hash, []byte("password")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestVerifyLimits(t *testing.T) {
+	for i, d := range []string{
+		"$argon2id$v=19$m=4294967295,t=1,p=1$YWJjZGVmZ2g$YWJjZA",
+		"$argon2id$v=19$m=2097153,t=1,p=1$YWJjZGVmZ2g$YWJjZA",
+		"$argon2id$v=19$m=64,t=4294967295,p=1$YWJjZGVmZ2g$YWJjZA",
+		"$argon2id$v=19$m=2097152,t=2,p=1$YWJjZGVmZ2g$YWJjZA",
+		"$argon2id$v=19$m=8192,t=1024,p=1$YWJjZGVmZ2g$YWJjZA",
+	} {
+		if err := New().Verify(d, []byte("x")); err != crypt.ErrSaltRounds {
+			t.Errorf("Test %d failed\nExpected: %v, got: %v", i, crypt.ErrSaltRounds, err)
+		}
+	}
+}
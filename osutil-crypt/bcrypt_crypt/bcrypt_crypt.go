@@ -0,0 +1,212 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+// Package bcrypt_crypt implements Niels Provos and David Mazières's bcrypt
+// password hashing algorithm, as used by OpenBSD.
+//
+// The specification for this algorithm can be found here:
+// http://www.usenix.org/event/usenix99/provos/provos.pdf
+package bcrypt_crypt
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"strconv"
+
+	"golang.org/x/crypto/blowfish"
+
+	"trident.li/go/osutil-crypt/common"
+)
+
+const (
+	MagicPrefix   = "$2b$"
+	SaltLenMin    = 22
+	SaltLenMax    = 22
+	RoundsMin     = 4 // The cost, as a base-2 logarithm of the rounds.
+	RoundsMax     = 31
+	RoundsDefault = 10
+)
+
+// Prefixes are the magic prefixes accepted when verifying a hashed key; "$2a$"
+// and "$2y$" only differ from "$2b$" in implementation bugs we do not have.
+var Prefixes = []string{"$2a$", "$2y$"}
+
+// magicCipherData is "OrpheanBeholderScryDoubt".
+var magicCipherData = []byte{
+	0x4f, 0x72, 0x70, 0x68,
+	0x65, 0x61, 0x6e, 0x42,
+	0x65, 0x68, 0x6f, 0x6c,
+	0x64, 0x65, 0x72, 0x53,
+	0x63, 0x72, 0x79, 0x44,
+	0x6f, 0x75, 0x62, 0x74,
+}
+
+var bcEncoding = base64.NewEncoding(
+	"./ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789",
+).WithPadding(base64.NoPadding)
+
+type crypter struct {
+	Salt crypt.Salt
+
+	cost int
+}
+
+// New returns a new crypt.Crypter computing the bcrypt password hashing.
+func New() crypt.Crypter {
+	return &crypter{
+		Salt: crypt.Salt{
+			MagicPrefix:   []byte(MagicPrefix),
+			SaltLenMin:    SaltLenMin,
+			SaltLenMax:    SaltLenMax,
+			RoundsDefault: RoundsDefault,
+			RoundsMin:     RoundsMin,
+			RoundsMax:     RoundsMax,
+		},
+		cost: RoundsDefault,
+	}
+}
+
+// Generate hashes the key with a salt of the form "$2b$<cost>$<salt>", where the
+// cost is a two-digit number and the salt 22 characters of bcrypt's Base64.
+func (c *crypter) Generate(key, salt []byte) (string, error) {
+	if len(salt) == 0 {
+		salt = c.generateSalt(c.cost)
+	}
+	if !c.hasPrefix(salt) {
+		return "", crypt.ErrSaltPrefix
+	}
+
+	saltToks := bytes.Split(salt, []byte{'$'})
+	if len(saltToks) < 4 || len(saltToks[3]) < SaltLenMax {
+		return "", crypt.ErrSaltFormat
+	}
+
+	cost, err := strconv.Atoi(string(saltToks[2]))
+	if err != nil || len(saltToks[2]) != 2 || cost < RoundsMin || cost > RoundsMax {
+		return "", crypt.ErrSaltRounds
+	}
+
+	salt = saltToks[3][:SaltLenMax]
+	csalt, err := bcEncoding.DecodeString(string(salt))
+	if err != nil {
+		return "", crypt.ErrSaltFormat
+	}
+
+	// The C implementations use the trailing NUL of the key string during the
+	// expansion; the key is copied to not change the caller's array.
+	ckey := make([]byte, len(key)+1)
+	copy(ckey, key)
+
+	cipher, err := blowfish.NewSaltedCipher(ckey, csalt)
+	if err != nil {
+		return "", err
+	}
+	for i := uint64(0); i < 1<<uint(cost); i++ {
+		blowfish.ExpandKey(ckey, cipher)
+		blowfish.ExpandKey(csalt, cipher)
+	}
+
+	cipherData := make([]byte, len(magicCipherData))
+	copy(cipherData, magicCipherData)
+	for i := 0; i < 24; i += 8 {
+		for j := 0; j < 64; j++ {
+			cipher.Encrypt(cipherData[i:i+8], cipherData[i:i+8])
+		}
+	}
+
+	out := make([]byte, 0, 60)
+	out = append(out, saltToks[0]...)
+	out = append(out, '$')
+	out = append(out, saltToks[1]...)
+	out = append(out, '$')
+	out = append(out, saltToks[2]...)
+	out = append(out, '$')
+	out = append(out, salt...)
+	// Only 23 of the 24 encrypted bytes are encoded, as the original does.
+	out = append(out, bcEncoding.EncodeToString(cipherData[:23])...)
+
+	// Clean sensitive data.
+	for i := range ckey {
+		ckey[i] = 0
+	}
+
+	return string(out), nil
+}
+
+func (c *crypter) Verify(hashedKey string, key []byte) error {
+	newHash, err := c.Generate(key, []byte(hashedKey))
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare([]byte(newHash), []byte(hashedKey)) != 1 {
+		return crypt.ErrKeyMismatch
+	}
+	return nil
+}
+
+func (c *crypter) Cost(hashedKey string) (int, error) {
+	saltToks := bytes.Split([]byte(hashedKey), []byte{'$'})
+	if len(saltToks) < 4 {
+		return 0, crypt.ErrSaltFormat
+	}
+	cost, err := strconv.ParseInt(string(saltToks[2]), 10, 0)
+	return int(cost), err
+}
+
+func (c *crypter) SetSalt(salt crypt.Salt) { c.Salt = salt }
+
+// SetParams sets the cost, as a base-2 logarithm of the rounds.
+func (c *crypter) SetParams(p crypt.Params) error {
+	if p.Rounds == 0 {
+		return nil
+	}
+	if p.Rounds < RoundsMin || p.Rounds > RoundsMax {
+		return crypt.ErrSaltRounds
+	}
+	c.cost = p.Rounds
+	return nil
+}
+
+func (c *crypter) Params(hashedKey string) (crypt.Params, error) {
+	cost, err := c.Cost(hashedKey)
+	return crypt.Params{Rounds: cost}, err
+}
+
+// generateSalt returns a random salt for the given cost.
+func (c *crypter) generateSalt(cost int) []byte {
+	if cost < c.Salt.RoundsMin {
+		cost = c.Salt.RoundsMin
+	} else if cost > c.Salt.RoundsMax {
+		cost = c.Salt.RoundsMax
+	}
+
+	raw := make([]byte, 16)
+	rand.Read(raw)
+
+	out := make([]byte, 0, len(c.Salt.MagicPrefix)+3+SaltLenMax)
+	out = append(out, c.Salt.MagicPrefix...)
+	if cost < 10 {
+		out = append(out, '0')
+	}
+	out = strconv.AppendInt(out, int64(cost), 10)
+	out = append(out, '$')
+	out = append(out, bcEncoding.EncodeToString(raw)...)
+	return out
+}
+
+func (c *crypter) hasPrefix(salt []byte) bool {
+	if bytes.HasPrefix(salt, c.Salt.MagicPrefix) {
+		return true
+	}
+	for _, p := range Prefixes {
+		if bytes.HasPrefix(salt, []byte(p)) {
+			return true
+		}
+	}
+	return false
+}
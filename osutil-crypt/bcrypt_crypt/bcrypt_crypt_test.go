@@ -0,0 +1,115 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package bcrypt_crypt
+
+import (
+	"testing"
+
+	"trident.li/go/osutil-crypt/common"
+)
+
+var bcryptCrypt = New()
+
+func TestGenerate(t *testing.T) {
+	data := []struct {
+		salt []byte
+		key  []byte
+		out  string
+		cost int
+	}{
+		{
+			[]byte("$2a$05$CCCCCCCCCCCCCCCCCCCCC."),
+			[]byte("U*U"),
+			"$2a$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW",
+			5,
+		},
+		{
+			[]byte("$2b$06$DCq7YPn5Rq63x1Lad4cll."),
+			[]byte(""),
+			"$2b$06$DCq7YPn5Rq63x1Lad4cll.TV4S6ytwfsfvkgY8jIucDrjc8deX1s.",
+			6,
+		},
+		{
+			[]byte("$2y$04$abcdefghijklmnopqrstuu"),
+			[]byte("password"),
+			"$2y$04$abcdefghijklmnopqrstuughE8Ev8uGFaUgY2cNEySvxngrb/Jzdm",
+			4,
+		},
+		{
+			[]byte("$2b$04$abcdefghijklmnopqrstuu"),
+			[]byte("0123456789012345678901234567890123456789" +
+				"012345678901234567890123456789012345"),
+			"$2b$04$abcdefghijklmnopqrstuum2G75IXDN/xsgbNa/hCiPSKyIHQd70S",
+			4,
+		},
+	}
+
+	for i, d := range data {
+		hash, err := bcryptCrypt.Generate(d.key, d.salt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hash != d.out {
+			t.Errorf("Test %d failed\nExpected: %s, got: %s", i, d.out, hash)
+		}
+
+		cost, err := bcryptCrypt.Cost(hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cost != d.cost {
+			t.Errorf("Test %d failed\nExpected: %d, got: %d", i, d.cost, cost)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	data := [][]byte{
+		[]byte("password"),
+		[]byte("12345"),
+		[]byte("That's amazing! I've got the same combination on my luggage!"),
+		[]byte("And change the combination on my luggage!"),
+		[]byte("         random  spa  c    ing."),
+		[]byte("94ajflkvjzpe8u3&*j1k513KLJ&*()"),
+	}
+	for i, d := range data {
+		hash, err := bcryptCrypt.Generate(d, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = bcryptCrypt.Verify(hash, d); err != nil {
+			t.Errorf("Test %d failed: %s", i, d)
+		}
+	}
+}
+
+func TestParams(t *testing.T) {
+	c := New()
+	want := crypt.Params{Rounds: 5}
+
+	if err := c.SetParams(crypt.Params{Rounds: RoundsMin - 1}); err != crypt.ErrSaltRounds {
+		t.Errorf("Expected ErrSaltRounds, got: %v", err)
+	}
+	if err := c.SetParams(want); err != nil {
+		t.Fatal(err)
+	}
+
+	hash, err := c.Generate([]byte("password"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params, err := c.Params(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params != want {
+		t.Errorf("Expected: %+v, got: %+v", want, params)
+	}
+	if err = c.Verify(hash, []byte("password")); err != nil {
+		t.Error(err)
+	}
+}
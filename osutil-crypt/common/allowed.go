@@ -0,0 +1,85 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package crypt
+
+// PolicyError is returned when a crypt function is not allowed by
+// SetAllowedAlgorithms.
+type PolicyError struct {
+	Crypt Crypt
+}
+
+func (e *PolicyError) Error() string {
+	return "crypt: crypt function not allowed: " + e.Crypt.String()
+}
+
+// allowed holds the crypt functions given to SetAllowedAlgorithms; nil allows
+// all of them. It is guarded by cryptsMu.
+var allowed map[Crypt]bool
+
+// SetAllowedAlgorithms restricts the crypt functions which can be used to the
+// given ones, for deployments with compliance requirements; i.e. a FIPS
+// deployment could only allow SHA256, SHA512, PBKDF2_SHA256 and PBKDF2_SHA512,
+// refusing MD5, APR1 and the DES-based crypt. Without arguments, all the crypt
+// functions are allowed again.
+//
+// The crypters returned by New and NewFromHash, and those built from them such
+// as the one of SystemDefault, return a *PolicyError from Generate and Verify
+// while their crypt function is not allowed, even if they were created before;
+// NewFromHash, Verify and Calibrate return it too. The crypters created
+// directly from their packages are not affected.
+func SetAllowedAlgorithms(cs ...Crypt) {
+	var m map[Crypt]bool
+	if len(cs) != 0 {
+		m = make(map[Crypt]bool, len(cs))
+		for _, c := range cs {
+			m[c] = true
+		}
+	}
+
+	cryptsMu.Lock()
+	allowed = m
+	cryptsMu.Unlock()
+}
+
+// Allowed reports whether the crypt function is allowed by
+// SetAllowedAlgorithms.
+func Allowed(c Crypt) bool {
+	cryptsMu.RLock()
+	defer cryptsMu.RUnlock()
+	return allowed == nil || allowed[c]
+}
+
+// checkAllowed returns a *PolicyError if the crypt function is not allowed.
+func checkAllowed(c Crypt) error {
+	if !Allowed(c) {
+		return &PolicyError{c}
+	}
+	return nil
+}
+
+// policyCrypter is the crypter returned by New and NewFromHash, which checks
+// that its crypt function is allowed each time a key is hashed, so that a later
+// call to SetAllowedAlgorithms applies to it too. The hashed keys can still be
+// inspected.
+type policyCrypter struct {
+	Crypter
+	c Crypt
+}
+
+func (c *policyCrypter) Generate(key, salt []byte) (string, error) {
+	if err := checkAllowed(c.c); err != nil {
+		return "", err
+	}
+	return c.Crypter.Generate(key, salt)
+}
+
+func (c *policyCrypter) Verify(hashedKey string, key []byte) error {
+	if err := checkAllowed(c.c); err != nil {
+		return err
+	}
+	return c.Crypter.Verify(hashedKey, key)
+}
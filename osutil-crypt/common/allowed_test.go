@@ -0,0 +1,75 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package crypt_test
+
+import (
+	"testing"
+	"time"
+
+	"trident.li/go/osutil-crypt/common"
+	"trident.li/go/osutil-crypt/md5_crypt"
+	"trident.li/go/osutil-crypt/sha512_crypt"
+)
+
+func TestSetAllowedAlgorithms(t *testing.T) {
+	md5Hash, err := md5_crypt.New().Generate([]byte("password"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sha512Hash, err := sha512_crypt.New().Generate([]byte("password"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before := crypt.New(crypt.MD5)
+
+	crypt.SetAllowedAlgorithms(crypt.SHA512, crypt.BCRYPT)
+	defer crypt.SetAllowedAlgorithms()
+
+	isPolicyError := func(err error, c crypt.Crypt) bool {
+		e, ok := err.(*crypt.PolicyError)
+		return ok && e.Crypt == c
+	}
+
+	if crypt.Allowed(crypt.MD5) || !crypt.Allowed(crypt.SHA512) {
+		t.Error("Expected only SHA512 and BCRYPT to be allowed")
+	}
+	if err = crypt.Verify(md5Hash, "password"); !isPolicyError(err, crypt.MD5) {
+		t.Errorf("Expected a policy error for MD5, got: %v", err)
+	}
+	if _, err = crypt.NewFromHash(md5Hash); !isPolicyError(err, crypt.MD5) {
+		t.Errorf("Expected a policy error for MD5, got: %v", err)
+	}
+	if err = crypt.Verify(sha512Hash, "password"); err != nil {
+		t.Error(err)
+	}
+
+	c := crypt.New(crypt.MD5)
+	if _, err = c.Generate([]byte("password"), nil); !isPolicyError(err, crypt.MD5) {
+		t.Errorf("Expected a policy error for MD5, got: %v", err)
+	}
+	if err = c.Verify(md5Hash, []byte("password")); !isPolicyError(err, crypt.MD5) {
+		t.Errorf("Expected a policy error for MD5, got: %v", err)
+	}
+	if _, err = before.Generate([]byte("password"), nil); !isPolicyError(err, crypt.MD5) {
+		t.Errorf("Expected a policy error for MD5, got: %v", err)
+	}
+	if _, err = crypt.New(crypt.SHA512).Generate([]byte("password"), nil); err != nil {
+		t.Error(err)
+	}
+	if _, err = crypt.Calibrate(crypt.MD5, time.Millisecond); !isPolicyError(err, crypt.MD5) {
+		t.Errorf("Expected a policy error for MD5, got: %v", err)
+	}
+
+	crypt.SetAllowedAlgorithms()
+	if err = crypt.Verify(md5Hash, "password"); err != nil {
+		t.Error(err)
+	}
+	if _, err = before.Generate([]byte("password"), nil); err != nil {
+		t.Error(err)
+	}
+}
@@ -10,7 +10,10 @@ package crypt
 
 import (
 	"errors"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 )
 
 var ErrKeyMismatch = errors.New("hashed value is not the hash of the given password")
@@ -29,6 +32,10 @@ type Crypter interface {
 	// Verify compares a hashed key with its possible key equivalent.
 	// Returns nil on success, or an error on failure; if the hashed key is
 	// different, the error is "ErrKeyMismatch".
+	//
+	// The hashes must be compared in constant time, with
+	// subtle.ConstantTimeCompare. Verify may only return early on errors in
+	// the hashed key, whose timing does not depend on the key.
 	Verify(hashedKey string, key []byte) error
 
 	// Cost returns the hashing cost (in rounds) used to create the given hashed
@@ -44,6 +51,17 @@ type Crypter interface {
 	// SetSalt sets a different salt. It is used to easily create derivated
 	// algorithms, i.e. "apr1_crypt" from "md5_crypt".
 	SetSalt(salt Salt)
+
+	// SetParams sets the parameters used by Generate when the salt is empty.
+	// It returns ErrSaltRounds if a parameter is out of the range of the crypt
+	// function, and then the crypter is not changed.
+	//
+	// The algorithms with a fixed cost only accept their fixed number of
+	// rounds.
+	SetParams(p Params) error
+
+	// Params returns the parameters used to create the given hashed key.
+	Params(hashedKey string) (Params, error)
 }
 
 // Crypt identifies a crypt function that is implemented in another package.
@@ -54,46 +72,140 @@ const (
 	MD5
 	SHA256
 	SHA512
+	BCRYPT
+	ARGON2ID
+	SCRYPT
+	YESCRYPT
+	PBKDF2_SHA256
+	PBKDF2_SHA512
+	DES
+	BSDI
+	NT   // Not a crypt(3) hash, so it is never returned by NewFromHash.
+	SHA1 // The "{SHA}" of htpasswd; only used by SetAllowedAlgorithms.
 	maxCrypt
 )
 
-var cryptPrefixes = make([]string, maxCrypt)
+// Custom is the first value of Crypt free for the crypt functions of other
+// projects.
+const Custom Crypt = 1000
 
-var crypts = make([]func() Crypter, maxCrypt)
+var cryptNames = [maxCrypt]string{
+	APR1:          "APR1",
+	MD5:           "MD5",
+	SHA256:        "SHA256",
+	SHA512:        "SHA512",
+	BCRYPT:        "BCRYPT",
+	ARGON2ID:      "ARGON2ID",
+	SCRYPT:        "SCRYPT",
+	YESCRYPT:      "YESCRYPT",
+	PBKDF2_SHA256: "PBKDF2_SHA256",
+	PBKDF2_SHA512: "PBKDF2_SHA512",
+	DES:           "DES",
+	BSDI:          "BSDI",
+	NT:            "NT",
+	SHA1:          "SHA1",
+}
+
+func (c Crypt) String() string {
+	if c > 0 && c < maxCrypt {
+		return cryptNames[c]
+	}
+	return "Crypt(" + strconv.FormatUint(uint64(c), 10) + ")"
+}
+
+var (
+	cryptsMu      sync.RWMutex
+	crypts        = make(map[Crypt]func() Crypter)
+	cryptPrefixes = make(map[Crypt][]string)
+)
 
 // RegisterCrypt registers a function that returns a new instance of the given
 // crypt function. This is intended to be called from the init function in
-// packages that implement crypt functions.
-func RegisterCrypt(c Crypt, f func() Crypter, prefix string) {
-	if c >= maxCrypt {
-		panic("crypt: RegisterHash of unknown crypt function")
+// packages that implement crypt functions; other projects can register their
+// own from Custom on.
+//
+// Any aliases are further prefixes recognized by NewFromHash for the same crypt
+// function, i.e. "$2a$" and "$2y$" for bcrypt. An empty prefix is never
+// recognized.
+//
+// The crypters returned by f must verify in constant time, as documented in
+// Crypter.
+//
+// It panics if the crypt function is already registered, or if a prefix is
+// already used by another one.
+func RegisterCrypt(c Crypt, f func() Crypter, prefix string, aliases ...string) {
+	if c == 0 || f == nil {
+		panic("crypt: RegisterCrypt of invalid crypt function")
+	}
+
+	cryptsMu.Lock()
+	defer cryptsMu.Unlock()
+
+	if crypts[c] != nil {
+		panic("crypt: RegisterCrypt called twice for " + c.String())
 	}
+	prefixes := append([]string{prefix}, aliases...)
+	for other, otherPrefixes := range cryptPrefixes {
+		for _, p := range prefixes {
+			for _, op := range otherPrefixes {
+				if p != "" && p == op {
+					panic("crypt: RegisterCrypt of " + c.String() +
+						" with the prefix of " + other.String() + ": " + p)
+				}
+			}
+		}
+	}
+
 	crypts[c] = f
-	cryptPrefixes[c] = prefix
+	cryptPrefixes[c] = prefixes
+}
+
+// UnregisterCrypt removes the registration of the given crypt function, if
+// any. It is mostly useful in tests.
+func UnregisterCrypt(c Crypt) {
+	cryptsMu.Lock()
+	delete(crypts, c)
+	delete(cryptPrefixes, c)
+	cryptsMu.Unlock()
 }
 
-// New returns a new crypter.
+// Algorithms returns the registered crypt functions, in increasing order.
+func Algorithms() []Crypt {
+	cryptsMu.RLock()
+	out := make([]Crypt, 0, len(crypts))
+	for c := range crypts {
+		out = append(out, c)
+	}
+	cryptsMu.RUnlock()
+
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+// lookup returns the function registered for the crypt function, or nil.
+func lookup(c Crypt) func() Crypter {
+	cryptsMu.RLock()
+	defer cryptsMu.RUnlock()
+	return crypts[c]
+}
+
+// New returns a new crypter. While the crypt function is not allowed by
+// SetAllowedAlgorithms, the crypter refuses to generate and verify hashed keys.
 func New(c Crypt) Crypter {
-	f := crypts[c]
+	f := lookup(c)
 	if f != nil {
-		return f()
+		return &policyCrypter{f(), c}
 	}
 	panic("crypt: requested cryp function is unavailable")
 }
 
 // NewFromHash returns a new Crypter using the prefix in the given hashed key.
+// It returns a *PolicyError if the crypt function is not allowed by
+// SetAllowedAlgorithms, and the crypter refuses to generate and verify hashed
+// keys while it is not.
 func NewFromHash(hashedKey string) (Crypter, error) {
-	var f func() Crypter
-
-	if strings.HasPrefix(hashedKey, cryptPrefixes[SHA512]) {
-		f = crypts[SHA512]
-	} else if strings.HasPrefix(hashedKey, cryptPrefixes[SHA256]) {
-		f = crypts[SHA256]
-	} else if strings.HasPrefix(hashedKey, cryptPrefixes[MD5]) {
-		f = crypts[MD5]
-	} else if strings.HasPrefix(hashedKey, cryptPrefixes[APR1]) {
-		f = crypts[APR1]
-	} else {
+	c := cryptFromHash(hashedKey)
+	if c == 0 {
 		toks := strings.SplitN(hashedKey, "$", 3)
 
 		if len(toks) < 2 {
@@ -105,9 +217,54 @@ func NewFromHash(hashedKey string) (Crypter, error) {
 		return nil, errors.New("crypt: unknown cryp function from prefix: " + prefix)
 	}
 
-	if f != nil {
-		return f(), nil
+	if f := lookup(c); f != nil {
+		if err := checkAllowed(c); err != nil {
+			return nil, err
+		}
+		return &policyCrypter{f(), c}, nil
 	}
 
 	return nil, errors.New("crypt: requested cryp function is unavailable")
 }
+
+// Verify compares a hashed key with its possible key equivalent, using the
+// crypt function given by the prefix of the hashed key. As every crypter, it
+// compares the hashes in constant time.
+//
+// Returns nil on success, ErrKeyMismatch if the hashed key is different, or
+// the error of NewFromHash, which is a *PolicyError if the crypt function is
+// not allowed.
+func Verify(hashedKey, key string) error {
+	c, err := NewFromHash(hashedKey)
+	if err != nil {
+		return err
+	}
+	return c.Verify(hashedKey, []byte(key))
+}
+
+// cryptFromHash returns the crypt function whose prefix matches the given
+// hashed key, or 0 if there is none. The longest prefix wins.
+func cryptFromHash(hashedKey string) Crypt {
+	cryptsMu.RLock()
+	defer cryptsMu.RUnlock()
+
+	var found Crypt
+	var foundLen int
+	for c, prefixes := range cryptPrefixes {
+		for _, prefix := range prefixes {
+			if prefix != "" && len(prefix) > foundLen && strings.HasPrefix(hashedKey, prefix) {
+				found, foundLen = c, len(prefix)
+			}
+		}
+	}
+	if found != 0 {
+		return found
+	}
+
+	// The traditional DES-based crypt has no prefix, only a fixed length.
+	if crypts[DES] != nil && len(hashedKey) == 13 &&
+		strings.Trim(hashedKey, alphabet) == "" {
+		return DES
+	}
+	return 0
+}
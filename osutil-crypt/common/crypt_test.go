@@ -0,0 +1,101 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package crypt_test
+
+import (
+	"reflect"
+	"testing"
+
+	"trident.li/go/osutil-crypt/common"
+	"trident.li/go/osutil-crypt/md5_crypt"
+	"trident.li/go/osutil-crypt/sha512_crypt"
+)
+
+func TestVerify(t *testing.T) {
+	data := []struct {
+		hash string
+		key  string
+		err  error
+	}{
+		{
+			"$6$rounds=1000$roundstoolow$kUMsbe306n21p9R.FRkW3IGn.S9NPN0x50YhH1xhLsPuWGsUSklZt58jaTfF4ZEQpyUNGc0dqbpBYYBaHHrsX.",
+			"the minimum number is still observed",
+			nil,
+		},
+		{
+			"$6$rounds=1000$roundstoolow$kUMsbe306n21p9R.FRkW3IGn.S9NPN0x50YhH1xhLsPuWGsUSklZt58jaTfF4ZEQpyUNGc0dqbpBYYBaHHrsX.",
+			"the minimum number is still observed!",
+			crypt.ErrKeyMismatch,
+		},
+		{
+			"$2a$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW",
+			"U*U",
+			nil,
+		},
+		{
+			"$2b$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW",
+			"U*V",
+			crypt.ErrKeyMismatch,
+		},
+		{"$6$rounds=10$roundstoolow", "", crypt.ErrKeyMismatch},
+		{"$5$rounds=7", "x", crypt.ErrSaltFormat},
+		{"$6$rounds=7", "x", crypt.ErrSaltFormat},
+	}
+
+	for i, d := range data {
+		if err := crypt.Verify(d.hash, d.key); err != d.err {
+			t.Errorf("Test %d failed\nExpected: %v, got: %v", i, d.err, err)
+		}
+	}
+
+	if err := crypt.Verify("$9$unknown", "key"); err == nil {
+		t.Error("Expected an error for an unknown prefix")
+	}
+}
+
+func TestRegisterCrypt(t *testing.T) {
+	custom := crypt.Custom + 1
+	crypt.RegisterCrypt(custom, md5_crypt.New, "$custom$", "$custom-old$")
+	defer crypt.UnregisterCrypt(custom)
+
+	want := []crypt.Crypt{crypt.MD5, crypt.SHA256, crypt.SHA512, crypt.BCRYPT, custom}
+	if got := crypt.Algorithms(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected: %v, got: %v", want, got)
+	}
+	if _, err := crypt.NewFromHash("$custom-old$salt$hash"); err != nil {
+		t.Error(err)
+	}
+
+	panics := func(f func()) (panicked bool) {
+		defer func() { panicked = recover() != nil }()
+		f()
+		return false
+	}
+	if !panics(func() { crypt.RegisterCrypt(custom, md5_crypt.New, "$other$") }) {
+		t.Error("Expected a panic registering a crypt function twice")
+	}
+	if !panics(func() { crypt.RegisterCrypt(custom+1, sha512_crypt.New, "$other$", "$6$") }) {
+		t.Error("Expected a panic registering a prefix twice")
+	}
+	if _, err := crypt.NewFromHash("$other$"); err == nil {
+		t.Error("Expected an error for a rejected registration")
+	}
+
+	crypt.UnregisterCrypt(custom)
+	if _, err := crypt.NewFromHash("$custom$salt$hash"); err == nil {
+		t.Error("Expected an error for an unregistered crypt function")
+	}
+}
+
+func TestCryptString(t *testing.T) {
+	if s := crypt.SHA512.String(); s != "SHA512" {
+		t.Errorf("Expected SHA512, got: %s", s)
+	}
+	if s := (crypt.Custom + 1).String(); s != "Crypt(1001)" {
+		t.Errorf("Expected Crypt(1001), got: %s", s)
+	}
+}
@@ -10,4 +10,11 @@
 //
 // Generally, you will never import this package directly. Many of the
 // *_crypt packages will import this package if they require it.
+//
+// The crypt functions which take their memory and time from the hashed key
+// bound them, since Verify runs with the parameters of any hashed key it is
+// given: running out of memory is a fatal error which cannot be recovered, and
+// a crafted hashed key could keep a CPU busy for minutes. A hashed key
+// exceeding the bounds of its crypt function is refused with ErrSaltRounds,
+// and so are parameters exceeding them in SetParams.
 package crypt
@@ -0,0 +1,172 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package crypt
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// Params represents the tunable parameters of a crypt function.
+//
+// Each crypt function only uses the fields which apply to it and ignores the
+// rest. In SetParams, a zero field leaves the current value unchanged.
+type Params struct {
+	// Rounds is the main cost: the rounds of SHA-crypt and PBKDF2, the cost of
+	// bcrypt, the base-2 logarithm of N of scrypt and yescrypt, the time of
+	// Argon2 and the count of the extended DES crypt.
+	Rounds int
+
+	// Memory is the memory of Argon2, in KiB.
+	Memory int
+
+	// Parallelism is the number of threads of Argon2, and p of scrypt and
+	// yescrypt.
+	Parallelism int
+
+	// BlockSize is r of scrypt and yescrypt.
+	BlockSize int
+}
+
+// Policy represents the crypt function and the minimum parameters which the
+// hashed keys should use.
+type Policy struct {
+	Crypt Crypt
+
+	// Params are the minimum parameters; a zero field is not checked.
+	Params Params
+}
+
+// NeedsRehash reports whether the hashed key should be replaced by a new one,
+// because it was not created by the crypt function of the policy or any of
+// its parameters is lower than the one of the policy. A hashed key which can
+// not be parsed also needs to be rehashed.
+//
+// It is intended to be called after a successful verification, when the key
+// is at hand to create the new hashed key.
+func NeedsRehash(hashedKey string, policy Policy) bool {
+	c := cryptFromHash(hashedKey)
+	if c == 0 || c != policy.Crypt {
+		return true
+	}
+	f := lookup(c)
+	if f == nil {
+		return true
+	}
+
+	p, err := f().Params(hashedKey)
+	if err != nil {
+		return true
+	}
+	min := policy.Params
+	return p.Rounds < min.Rounds || p.Memory < min.Memory ||
+		p.Parallelism < min.Parallelism || p.BlockSize < min.BlockSize
+}
+
+// logRounds are the crypt functions whose rounds are a base-2 logarithm.
+var logRounds = map[Crypt]bool{BCRYPT: true, SCRYPT: true, YESCRYPT: true}
+
+// calibrationKey is hashed to measure the duration of a crypt function.
+var calibrationKey = []byte("calibration password")
+
+// Calibrate returns the parameters with which the crypt function takes about
+// the target duration to hash a key on this machine, so that a suitable cost
+// can be chosen at install time.
+//
+// Only the rounds are changed, starting from the default parameters; i.e.
+// Argon2 keeps its memory. If the target is out of the range of the crypt
+// function, the rounds are clamped to it. It returns ErrSaltRounds if the cost
+// of the crypt function is fixed, or a *PolicyError if the crypt function is
+// not allowed.
+func Calibrate(c Crypt, target time.Duration) (Params, error) {
+	f := lookup(c)
+	if f == nil {
+		return Params{}, errors.New("crypt: requested cryp function is unavailable")
+	}
+	if err := checkAllowed(c); err != nil {
+		return Params{}, err
+	}
+	crypter := f()
+
+	start := time.Now()
+	hash, err := crypter.Generate(calibrationKey, nil)
+	if err != nil {
+		return Params{}, err
+	}
+	d := time.Since(start)
+	p, err := crypter.Params(hash)
+	if err != nil {
+		return Params{}, err
+	}
+	if crypter.SetParams(Params{Rounds: p.Rounds + 1}) != nil {
+		return Params{}, ErrSaltRounds // The cost is fixed.
+	}
+
+	measure := func(rounds int) (time.Duration, error) {
+		if err := crypter.SetParams(Params{Rounds: rounds}); err != nil {
+			return 0, err
+		}
+		start := time.Now()
+		_, err := crypter.Generate(calibrationKey, nil)
+		return time.Since(start), err
+	}
+
+	if logRounds[c] {
+		// Each step doubles or halves the duration; stop at the one closest
+		// to the target.
+		for float64(d)*math.Sqrt2 < float64(target) {
+			next, err := measure(p.Rounds + 1)
+			if err != nil {
+				break
+			}
+			p.Rounds, d = p.Rounds+1, next
+		}
+		for float64(d)/math.Sqrt2 > float64(target) {
+			next, err := measure(p.Rounds - 1)
+			if err != nil {
+				break
+			}
+			p.Rounds, d = p.Rounds-1, next
+		}
+		return p, nil
+	}
+
+	// The duration is linear in the rounds: scale them twice, the second
+	// time from a longer and so more accurate measure.
+	for i := 0; i < 2; i++ {
+		rounds := math.Max(1, math.Min(float64(p.Rounds)*float64(target)/float64(d), math.MaxInt32))
+		if clamped := clampRounds(crypter, p.Rounds, int(rounds)); clamped != int(rounds) {
+			// The target is out of the range of the crypt function.
+			p.Rounds = clamped
+			return p, nil
+		}
+		next, err := measure(int(rounds))
+		if err != nil {
+			return Params{}, err
+		}
+		p.Rounds, d = int(rounds), next
+	}
+	return p, nil
+}
+
+// clampRounds returns the rounds accepted by the crypter which are the closest
+// to the given ones, searching from the valid ones.
+func clampRounds(c Crypter, valid, rounds int) int {
+	if c.SetParams(Params{Rounds: rounds}) == nil {
+		return rounds
+	}
+	for rounds-valid > 1 || valid-rounds > 1 {
+		mid := valid + (rounds-valid)/2
+		if c.SetParams(Params{Rounds: mid}) == nil {
+			valid = mid
+		} else {
+			rounds = mid
+		}
+	}
+	return valid
+}
@@ -0,0 +1,103 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package crypt_test
+
+import (
+	"testing"
+	"time"
+
+	"trident.li/go/osutil-crypt/bcrypt_crypt"
+	"trident.li/go/osutil-crypt/common"
+	"trident.li/go/osutil-crypt/md5_crypt"
+	"trident.li/go/osutil-crypt/sha256_crypt"
+	"trident.li/go/osutil-crypt/sha512_crypt"
+)
+
+func init() {
+	crypt.RegisterCrypt(crypt.MD5, md5_crypt.New, md5_crypt.MagicPrefix)
+	crypt.RegisterCrypt(crypt.SHA256, sha256_crypt.New, sha256_crypt.MagicPrefix)
+	crypt.RegisterCrypt(crypt.SHA512, sha512_crypt.New, sha512_crypt.MagicPrefix)
+	crypt.RegisterCrypt(crypt.BCRYPT, bcrypt_crypt.New, bcrypt_crypt.MagicPrefix,
+		bcrypt_crypt.Prefixes...)
+}
+
+func TestNeedsRehash(t *testing.T) {
+	policy := crypt.Policy{Crypt: crypt.SHA512, Params: crypt.Params{Rounds: 10000}}
+
+	data := []struct {
+		hash string
+		out  bool
+	}{
+		{"$6$rounds=10000$saltstring$", false},
+		{"$6$rounds=656000$saltstring$", false},
+		{"$6$rounds=9999$saltstring$", true},
+		{"$6$saltstring$", true}, // 5000 rounds by default.
+		{"$2b$12$DCq7YPn5Rq63x1Lad4cll.TV4S6ytwfsfvkgY8jIucDrjc8deX1s.", true},
+		{"$6$rounds=x$saltstring$", true},
+		{"$9$unknown", true},
+		{"", true},
+	}
+
+	for i, d := range data {
+		if out := crypt.NeedsRehash(d.hash, policy); out != d.out {
+			t.Errorf("Test %d failed\nExpected: %v, got: %v", i, d.out, out)
+		}
+	}
+
+	policy = crypt.Policy{Crypt: crypt.BCRYPT}
+	if crypt.NeedsRehash("$2a$04$UuTkLRZZ6QofpDOlMz32MuuxEHA43WOemOYHPz6.SjsVsyO1tDU96", policy) {
+		t.Error("Expected no rehash for any cost")
+	}
+}
+
+func TestCalibrate(t *testing.T) {
+	short, err := crypt.Calibrate(crypt.SHA512, 5*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	long, err := crypt.Calibrate(crypt.SHA512, 40*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if long.Rounds <= short.Rounds {
+		t.Errorf("Expected more rounds than %d, got: %d", short.Rounds, long.Rounds)
+	}
+
+	p, err := crypt.Calibrate(crypt.BCRYPT, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Rounds >= bcrypt_crypt.RoundsDefault {
+		t.Errorf("Expected a cost lower than %d, got: %d", bcrypt_crypt.RoundsDefault, p.Rounds)
+	}
+
+	// Targets out of the range of the crypt functions.
+	for i, d := range []struct {
+		c      crypt.Crypt
+		target time.Duration
+		rounds int
+	}{
+		{crypt.SHA512, 2 * time.Hour, sha512_crypt.RoundsMax},
+		{crypt.SHA512, time.Nanosecond, sha512_crypt.RoundsMin},
+		{crypt.BCRYPT, time.Nanosecond, bcrypt_crypt.RoundsMin},
+	} {
+		p, err := crypt.Calibrate(d.c, d.target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Rounds != d.rounds {
+			t.Errorf("Test %d failed\nExpected: %d, got: %d", i, d.rounds, p.Rounds)
+		}
+	}
+
+	if _, err = crypt.Calibrate(crypt.MD5, time.Second); err != crypt.ErrSaltRounds {
+		t.Errorf("Expected ErrSaltRounds, got: %v", err)
+	}
+	if _, err = crypt.Calibrate(crypt.APR1, time.Second); err == nil {
+		t.Error("Expected an error for an unregistered crypt function")
+	}
+}
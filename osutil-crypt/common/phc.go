@@ -0,0 +1,164 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package crypt
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+)
+
+// phcEncoding is the Base64 of the PHC string format: standard, without
+// padding, and canonical.
+var phcEncoding = base64.RawStdEncoding.Strict()
+
+// PHC represents a hashed key in the PHC string format:
+//
+//	$<id>[$v=<version>][$<param>=<value>(,<param>=<value>)*][$<salt>[$<hash>]]
+//
+// where the salt and the hash are in standard Base64 without padding.
+//
+// The specification of this format can be found here:
+// https://github.com/P-H-C/phc-string-format/blob/master/phc-sf-spec.md
+type PHC struct {
+	ID      string
+	Version int // Zero if there is no version.
+	Params  []PHCParam
+	Salt    []byte // Nil if there is no salt.
+	Hash    []byte // Nil if there is no hash.
+}
+
+// PHCParam is a parameter of a PHC string, which keeps its order.
+type PHCParam struct {
+	Name, Value string
+}
+
+// ParsePHC parses a string in the PHC string format. It returns ErrSaltFormat
+// if the string does not follow the format.
+func ParsePHC(s string) (*PHC, error) {
+	if !strings.HasPrefix(s, "$") {
+		return nil, ErrSaltFormat
+	}
+	fields := strings.Split(s[1:], "$")
+
+	h := &PHC{ID: fields[0]}
+	if !isPHCName(h.ID) {
+		return nil, ErrSaltFormat
+	}
+	fields = fields[1:]
+
+	if len(fields) != 0 && strings.HasPrefix(fields[0], "v=") {
+		v, err := strconv.ParseUint(fields[0][2:], 10, 31)
+		if err != nil {
+			return nil, ErrSaltFormat
+		}
+		h.Version = int(v)
+		fields = fields[1:]
+	}
+
+	if len(fields) != 0 && strings.Contains(fields[0], "=") {
+		for _, param := range strings.Split(fields[0], ",") {
+			i := strings.IndexByte(param, '=')
+			if i == -1 || !isPHCName(param[:i]) || !isPHCValue(param[i+1:]) {
+				return nil, ErrSaltFormat
+			}
+			h.Params = append(h.Params, PHCParam{param[:i], param[i+1:]})
+		}
+		fields = fields[1:]
+	}
+
+	if len(fields) > 2 {
+		return nil, ErrSaltFormat
+	}
+	var err error
+	if len(fields) > 0 {
+		if h.Salt, err = phcEncoding.DecodeString(fields[0]); err != nil {
+			return nil, ErrSaltFormat
+		}
+	}
+	if len(fields) > 1 {
+		if h.Hash, err = phcEncoding.DecodeString(fields[1]); err != nil {
+			return nil, ErrSaltFormat
+		}
+	}
+
+	return h, nil
+}
+
+// Param returns the value of the named parameter, and whether it is present.
+func (h *PHC) Param(name string) (string, bool) {
+	for _, p := range h.Params {
+		if p.Name == name {
+			return p.Value, true
+		}
+	}
+	return "", false
+}
+
+// String returns the PHC string; the salt is omitted when it is nil, and so
+// the hash.
+func (h *PHC) String() string {
+	out := make([]byte, 0, 64)
+	out = append(out, '$')
+	out = append(out, h.ID...)
+
+	if h.Version != 0 {
+		out = append(out, "$v="...)
+		out = strconv.AppendInt(out, int64(h.Version), 10)
+	}
+
+	for i, p := range h.Params {
+		if i == 0 {
+			out = append(out, '$')
+		} else {
+			out = append(out, ',')
+		}
+		out = append(out, p.Name...)
+		out = append(out, '=')
+		out = append(out, p.Value...)
+	}
+
+	if h.Salt != nil {
+		out = append(out, '$')
+		out = append(out, phcEncoding.EncodeToString(h.Salt)...)
+
+		if h.Hash != nil {
+			out = append(out, '$')
+			out = append(out, phcEncoding.EncodeToString(h.Hash)...)
+		}
+	}
+
+	return string(out)
+}
+
+// isPHCName reports whether s is a valid function or parameter name.
+func isPHCName(s string) bool {
+	if len(s) == 0 || len(s) > 32 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; !('a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// isPHCValue reports whether s is a valid parameter value.
+func isPHCValue(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+			c == '/' || c == '+' || c == '.' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,77 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package crypt
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParsePHC(t *testing.T) {
+	data := []struct {
+		in   string
+		id   string
+		v    int
+		n    int // Number of parameters.
+		salt []byte
+		hash []byte
+	}{
+		{"$argon2id", "argon2id", 0, 0, nil, nil},
+		{"$argon2id$v=19", "argon2id", 19, 0, nil, nil},
+		{"$argon2id$v=19$m=65536,t=3,p=4", "argon2id", 19, 3, nil, nil},
+		{"$argon2id$v=19$m=65536,t=3,p=4$c29tZXNhbHQ", "argon2id", 19, 3, []byte("somesalt"), nil},
+		{
+			"$argon2id$v=19$m=65536,t=3,p=4$c29tZXNhbHQ$aGFzaA",
+			"argon2id", 19, 3, []byte("somesalt"), []byte("hash"),
+		},
+		{"$scrypt$ln=15,r=8,p=1$c2FsdA$aGFzaA", "scrypt", 0, 3, []byte("salt"), []byte("hash")},
+		{"$pbkdf2-sha256$c2FsdA", "pbkdf2-sha256", 0, 0, []byte("salt"), nil},
+	}
+
+	for i, d := range data {
+		h, err := ParsePHC(d.in)
+		if err != nil {
+			t.Fatalf("Test %d failed: %v", i, err)
+		}
+		if h.ID != d.id || h.Version != d.v || len(h.Params) != d.n ||
+			!bytes.Equal(h.Salt, d.salt) || !bytes.Equal(h.Hash, d.hash) {
+			t.Errorf("Test %d failed, got: %+v", i, h)
+		}
+		if out := h.String(); out != d.in {
+			t.Errorf("Test %d failed\nExpected: %s, got: %s", i, d.in, out)
+		}
+	}
+
+	h, _ := ParsePHC("$scrypt$ln=15,r=8,p=1")
+	if v, ok := h.Param("r"); !ok || v != "8" {
+		t.Errorf("Expected r=8, got: %q, %v", v, ok)
+	}
+	if _, ok := h.Param("x"); ok {
+		t.Error("Expected no parameter x")
+	}
+}
+
+func TestParsePHCError(t *testing.T) {
+	data := []string{
+		"",
+		"argon2id",
+		"$",
+		"$Argon2id",
+		"$argon2id$v=x",
+		"$argon2id$m=65536,t",
+		"$argon2id$m=65536,T=3",
+		"$argon2id$c29tZXNhbHQ$aGFzaA$aGFzaA",
+		"$argon2id$c29tZXNhbHQ=",
+		"$argon2id$c29tZXNhbHR", // Not canonical.
+	}
+
+	for i, d := range data {
+		if _, err := ParsePHC(d); err != ErrSaltFormat {
+			t.Errorf("Test %d failed\nExpected: %v, got: %v", i, ErrSaltFormat, err)
+		}
+	}
+}
@@ -94,7 +94,7 @@ func (s *Salt) GenerateWRounds(length, rounds int) []byte {
 
 	roundsText := ""
 	if rounds != s.RoundsDefault {
-		roundsText = "rounds=" + strconv.Itoa(rounds)
+		roundsText = "rounds=" + strconv.Itoa(rounds) + "$"
 	}
 
 	out := make([]byte, len(s.MagicPrefix)+len(roundsText)+length)
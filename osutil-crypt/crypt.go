@@ -9,11 +9,21 @@
 package crypt
 
 import (
+	"sync"
+	"time"
+
 	"trident.li/go/osutil-crypt/apr1_crypt"
+	"trident.li/go/osutil-crypt/argon2_crypt"
+	"trident.li/go/osutil-crypt/bcrypt_crypt"
 	"trident.li/go/osutil-crypt/common"
+	"trident.li/go/osutil-crypt/des_crypt"
 	"trident.li/go/osutil-crypt/md5_crypt"
+	"trident.li/go/osutil-crypt/nt_crypt"
+	"trident.li/go/osutil-crypt/pbkdf2_crypt"
+	"trident.li/go/osutil-crypt/scrypt_crypt"
 	"trident.li/go/osutil-crypt/sha256_crypt"
 	"trident.li/go/osutil-crypt/sha512_crypt"
+	"trident.li/go/osutil-crypt/yescrypt_crypt"
 )
 
 func init() {
@@ -21,8 +31,56 @@ func init() {
 	crypt.RegisterCrypt(crypt.MD5, md5_crypt.New, md5_crypt.MagicPrefix)
 	crypt.RegisterCrypt(crypt.SHA256, sha256_crypt.New, sha256_crypt.MagicPrefix)
 	crypt.RegisterCrypt(crypt.SHA512, sha512_crypt.New, sha512_crypt.MagicPrefix)
+	crypt.RegisterCrypt(crypt.BCRYPT, bcrypt_crypt.New, bcrypt_crypt.MagicPrefix,
+		bcrypt_crypt.Prefixes...)
+	crypt.RegisterCrypt(crypt.ARGON2ID, argon2_crypt.New, argon2_crypt.MagicPrefix)
+	crypt.RegisterCrypt(crypt.SCRYPT, scrypt_crypt.New, scrypt_crypt.MagicPrefix)
+	crypt.RegisterCrypt(crypt.YESCRYPT, yescrypt_crypt.New, yescrypt_crypt.MagicPrefix)
+	crypt.RegisterCrypt(crypt.PBKDF2_SHA256, pbkdf2_crypt.NewSHA256, pbkdf2_crypt.MagicPrefixSHA256)
+	crypt.RegisterCrypt(crypt.PBKDF2_SHA512, pbkdf2_crypt.NewSHA512, pbkdf2_crypt.MagicPrefixSHA512)
+	crypt.RegisterCrypt(crypt.NT, nt_crypt.New, nt_crypt.MagicPrefix)
+}
+
+var enableDES sync.Once
+
+// EnableDES registers the traditional and the extended DES-based crypt, so that
+// NewFromHash recognizes their hashed keys. They are not registered by default
+// since they are only fit to verify the keys of old password files.
+//
+// Since the extended crypt takes its count from the hashed key, and its highest
+// count takes about a minute, the counts above des_crypt.RoundsLimitExtended
+// are refused with crypt.ErrSaltRounds.
+func EnableDES() {
+	enableDES.Do(func() {
+		crypt.RegisterCrypt(crypt.DES, des_crypt.New, des_crypt.MagicPrefix)
+		crypt.RegisterCrypt(crypt.BSDI, des_crypt.NewExtended, des_crypt.MagicPrefixExtended)
+	})
+}
+
+// SetAllowedAlgorithms restricts the crypt functions which can be used to the
+// given ones. See crypt.SetAllowedAlgorithms.
+func SetAllowedAlgorithms(cs ...crypt.Crypt) {
+	crypt.SetAllowedAlgorithms(cs...)
 }
 
 func NewFromHash(hashedKey string) (crypt.Crypter, error) {
 	return crypt.NewFromHash(hashedKey)
 }
+
+// Verify compares a hashed key with its possible key equivalent, detecting the
+// crypt function from the hashed key. See crypt.Verify.
+func Verify(hashedKey, key string) error {
+	return crypt.Verify(hashedKey, key)
+}
+
+// NeedsRehash reports whether the hashed key does not follow the policy, so a
+// new one should be created. See crypt.NeedsRehash.
+func NeedsRehash(hashedKey string, policy crypt.Policy) bool {
+	return crypt.NeedsRehash(hashedKey, policy)
+}
+
+// Calibrate returns the parameters with which the crypt function takes about
+// the target duration. See crypt.Calibrate.
+func Calibrate(c crypt.Crypt, target time.Duration) (crypt.Params, error) {
+	return crypt.Calibrate(c, target)
+}
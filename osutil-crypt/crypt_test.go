@@ -0,0 +1,245 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package crypt
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"trident.li/go/osutil-crypt/apr1_crypt"
+	"trident.li/go/osutil-crypt/argon2_crypt"
+	"trident.li/go/osutil-crypt/bcrypt_crypt"
+	"trident.li/go/osutil-crypt/common"
+	"trident.li/go/osutil-crypt/des_crypt"
+	"trident.li/go/osutil-crypt/htpasswd"
+	"trident.li/go/osutil-crypt/md5_crypt"
+	"trident.li/go/osutil-crypt/nt_crypt"
+	"trident.li/go/osutil-crypt/pbkdf2_crypt"
+	"trident.li/go/osutil-crypt/pepper_crypt"
+	"trident.li/go/osutil-crypt/scrypt_crypt"
+	"trident.li/go/osutil-crypt/sha256_crypt"
+	"trident.li/go/osutil-crypt/sha512_crypt"
+	"trident.li/go/osutil-crypt/yescrypt_crypt"
+)
+
+// TestVerifyConstantTime checks the source of every crypter of the packages of
+// this repository, since timing can not be measured reliably: each Verify has
+// to call subtle.ConstantTimeCompare or another Verify, and can only use == and
+// != to check errors and lengths.
+func TestVerifyConstantTime(t *testing.T) {
+	files, err := filepath.Glob("*/*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fset := token.NewFileSet()
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || fn.Name.Name != "Verify" {
+				continue
+			}
+
+			// The variables holding the result of another Verify.
+			delegated := make(map[string]bool)
+
+			var compares bool
+			ast.Inspect(fn.Body, func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.CallExpr:
+					switch name := qualifiedName(n.Fun); name {
+					case "subtle.ConstantTimeCompare", "crypt.Verify":
+						compares = true
+					case "bytes.Equal", "bytes.EqualFold", "bytes.Compare",
+						"strings.EqualFold", "strings.Compare", "reflect.DeepEqual":
+						t.Errorf("%s: comparison not in constant time: %s",
+							fset.Position(n.Pos()), name)
+					}
+				case *ast.AssignStmt:
+					if len(n.Rhs) == 1 && isVerifyCall(n.Rhs[0]) {
+						for _, lhs := range n.Lhs {
+							if id, ok := lhs.(*ast.Ident); ok {
+								delegated[id.Name] = true
+							}
+						}
+					}
+				case *ast.ReturnStmt:
+					// Returning the result of another Verify delegates the
+					// comparison to it.
+					for _, r := range n.Results {
+						if id, ok := r.(*ast.Ident); ok && delegated[id.Name] || isVerifyCall(r) {
+							compares = true
+						}
+					}
+				case *ast.BinaryExpr:
+					if (n.Op == token.EQL || n.Op == token.NEQ) &&
+						!isErrCheck(n.X) && !isErrCheck(n.Y) {
+						t.Errorf("%s: comparison not in constant time",
+							fset.Position(n.Pos()))
+					}
+				}
+				return true
+			})
+			if !compares {
+				t.Errorf("%s: Verify does not call subtle.ConstantTimeCompare",
+					fset.Position(fn.Pos()))
+			}
+		}
+	}
+}
+
+// qualifiedName returns the name of the function called as "pkg.Func", or "".
+func qualifiedName(e ast.Expr) string {
+	sel, ok := e.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	x, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+	return x.Name + "." + sel.Sel.Name
+}
+
+// isVerifyCall reports whether e is a call to the Verify method of a value.
+func isVerifyCall(e ast.Expr) bool {
+	call, ok := e.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == "Verify"
+}
+
+// isErrCheck reports whether e is nil, an integer or a length, the operands
+// allowed to check errors and lengths, which do not depend on the key.
+func isErrCheck(e ast.Expr) bool {
+	switch e := e.(type) {
+	case *ast.Ident:
+		return e.Name == "nil"
+	case *ast.BasicLit:
+		return e.Kind == token.INT
+	case *ast.CallExpr:
+		fn, ok := e.Fun.(*ast.Ident)
+		return ok && fn.Name == "len"
+	}
+	return false
+}
+
+// TestVerifyTampered checks that tampering with the salt, the parameters or the
+// digest of a hashed key gives ErrKeyMismatch, through the same comparison.
+// The hashed keys are generated from the settings, and the tampered settings
+// replace them keeping the digest.
+func TestVerifyTampered(t *testing.T) {
+	EnableDES()
+	pepper, err := pepper_crypt.New(sha512_crypt.New(), "k1", []byte("a secret pepper"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := []struct {
+		c        crypt.Crypter
+		setting  string
+		tampered []string // The salt and the parameters.
+	}{
+		{apr1_crypt.New(), "$apr1$12345678", []string{"$apr1$22345678"}},
+		{md5_crypt.New(), "$1$12345678", []string{"$1$22345678"}},
+		{
+			sha256_crypt.New(), "$5$rounds=1000$saltstring",
+			[]string{"$5$rounds=1000$Saltstring", "$5$rounds=1001$saltstring"},
+		},
+		{
+			sha512_crypt.New(), "$6$rounds=1000$saltstring",
+			[]string{"$6$rounds=1000$Saltstring", "$6$rounds=1001$saltstring"},
+		},
+		{
+			bcrypt_crypt.New(), "$2b$04$CCCCCCCCCCCCCCCCCCCCC.",
+			[]string{"$2b$04$DCCCCCCCCCCCCCCCCCCCC.", "$2b$05$CCCCCCCCCCCCCCCCCCCCC."},
+		},
+		{
+			argon2_crypt.New(), "$argon2id$v=19$m=64,t=1,p=1$c2FsdHNhbHQ",
+			[]string{
+				"$argon2id$v=19$m=64,t=1,p=1$d2FsdHNhbHQ",
+				"$argon2id$v=19$m=64,t=2,p=1$c2FsdHNhbHQ",
+				"$argon2id$v=19$m=72,t=1,p=1$c2FsdHNhbHQ",
+			},
+		},
+		{
+			scrypt_crypt.New(), "$7$2/..../....saltstring",
+			[]string{
+				"$7$2/..../....Saltstring",
+				"$7$3/..../....saltstring",
+				"$7$20..../....saltstring",
+				"$7$2/....0....saltstring",
+			},
+		},
+		{
+			yescrypt_crypt.New(), "$y$j75$abcdefgh",
+			[]string{"$y$j75$bbcdefgh", "$y$j85$abcdefgh", "$y$j76$abcdefgh"},
+		},
+		{
+			pbkdf2_crypt.NewSHA256(), "$pbkdf2-sha256$1000$c2FsdHNhbHQ",
+			[]string{"$pbkdf2-sha256$1000$d2FsdHNhbHQ", "$pbkdf2-sha256$1001$c2FsdHNhbHQ"},
+		},
+		{
+			pbkdf2_crypt.NewSHA512(), "$pbkdf2-sha512$1000$c2FsdHNhbHQ",
+			[]string{"$pbkdf2-sha512$1000$d2FsdHNhbHQ", "$pbkdf2-sha512$1001$c2FsdHNhbHQ"},
+		},
+		{des_crypt.New(), "ab", []string{"bb"}},
+		{des_crypt.NewExtended(), "_J9..abcd", []string{"_J9..bbcd", "_K9..abcd"}},
+		{nt_crypt.New(), "", nil},    // No salt nor parameters.
+		{htpasswd.NewSHA(), "", nil}, // No salt nor parameters.
+		{
+			pepper, "$pepper$k1$6$rounds=1000$saltstring",
+			[]string{"$pepper$k1$6$rounds=1000$Saltstring", "$pepper$k1$6$rounds=1001$saltstring"},
+		},
+	}
+
+	key := []byte("Hello world!")
+	for i, d := range data {
+		hash, err := d.c.Generate(key, []byte(d.setting))
+		if err != nil {
+			t.Fatalf("Test %d failed: %v", i, err)
+		}
+		if !strings.HasPrefix(hash, d.setting) {
+			t.Fatalf("Test %d failed\nExpected prefix: %s, got: %s", i, d.setting, hash)
+		}
+		if err = d.c.Verify(hash, key); err != nil {
+			t.Errorf("Test %d failed: %v", i, err)
+		}
+
+		// The last character of the digest, before any padding.
+		b := []byte(hash)
+		j := len(strings.TrimRight(hash, "=")) - 1
+		if b[j] == '0' {
+			b[j] = '1'
+		} else {
+			b[j] = '0'
+		}
+		for _, setting := range d.tampered {
+			tampered := setting + hash[len(d.setting):]
+			if err = d.c.Verify(tampered, key); err != crypt.ErrKeyMismatch {
+				t.Errorf("Test %d failed: %s\nExpected: %v, got: %v", i, tampered, crypt.ErrKeyMismatch, err)
+			}
+		}
+		if err = d.c.Verify(string(b), key); err != crypt.ErrKeyMismatch {
+			t.Errorf("Test %d failed: %s\nExpected: %v, got: %v", i, b, crypt.ErrKeyMismatch, err)
+		}
+	}
+}
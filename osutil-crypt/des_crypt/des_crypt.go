@@ -0,0 +1,270 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+// Package des_crypt implements the traditional DES-based Unix crypt and its
+// extended BSDi variant.
+//
+// Both are broken by today's standards: they are only meant to verify the
+// hashed keys of old password files, so that these can be migrated. Hence a
+// crypter of this package refuses to generate a hashed key with a random salt.
+package des_crypt
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"errors"
+
+	"trident.li/go/osutil-crypt/common"
+)
+
+const (
+	MagicPrefix   = "" // The traditional crypt has no magic prefix.
+	SaltLenMin    = 2
+	SaltLenMax    = 2
+	RoundsDefault = 25
+
+	MagicPrefixExtended   = "_"
+	SaltLenExtended       = 4
+	RoundsMinExtended     = 1
+	RoundsMaxExtended     = 1<<24 - 1
+	RoundsDefaultExtended = 725
+
+	// RoundsLimitExtended is the highest count accepted, which takes about 3
+	// seconds of a CPU; the count of a hashed key is not bounded otherwise,
+	// and RoundsMaxExtended takes about a minute.
+	RoundsLimitExtended = 1 << 20
+)
+
+const alphabet = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// ErrGenerate is returned when a hashed key is requested without giving a
+// salt, since new DES-based hashes should not be created.
+var ErrGenerate = errors.New("des_crypt: generating DES-based hashes is not allowed")
+
+type crypter struct{ Salt crypt.Salt }
+
+type extendedCrypter struct{ Salt crypt.Salt }
+
+// New returns a new crypt.Crypter computing the traditional DES-based crypt,
+// whose hashed keys are 13 characters long.
+func New() crypt.Crypter {
+	return &crypter{
+		crypt.Salt{
+			MagicPrefix:   []byte(MagicPrefix),
+			SaltLenMin:    SaltLenMin,
+			SaltLenMax:    SaltLenMax,
+			RoundsDefault: RoundsDefault,
+		},
+	}
+}
+
+// NewExtended returns a new crypt.Crypter computing the extended DES-based
+// crypt of BSDi, whose hashed keys start with "_".
+func NewExtended() crypt.Crypter {
+	return &extendedCrypter{
+		crypt.Salt{
+			MagicPrefix:   []byte(MagicPrefixExtended),
+			SaltLenMin:    SaltLenExtended,
+			SaltLenMax:    SaltLenExtended,
+			RoundsDefault: RoundsDefaultExtended,
+			RoundsMin:     RoundsMinExtended,
+			RoundsMax:     RoundsMaxExtended,
+		},
+	}
+}
+
+// IsHash reports whether hashedKey has the form of a traditional DES-based
+// hashed key.
+func IsHash(hashedKey string) bool {
+	if len(hashedKey) != 13 {
+		return false
+	}
+	for i := 0; i < len(hashedKey); i++ {
+		if bytes.IndexByte([]byte(alphabet), hashedKey[i]) == -1 {
+			return false
+		}
+	}
+	return true
+}
+
+// Generate hashes the first 8 characters of the key with a salt of 2
+// characters.
+func (c *crypter) Generate(key, salt []byte) (string, error) {
+	if len(salt) == 0 {
+		return "", ErrGenerate
+	}
+	if len(salt) < SaltLenMin {
+		return "", crypt.ErrSaltFormat
+	}
+
+	s, ok := decodeInt(salt[:2])
+	if !ok {
+		return "", crypt.ErrSaltFormat
+	}
+
+	var keyBlock uint64
+	for i := 0; i < 8; i++ {
+		keyBlock <<= 8
+		if i < len(key) {
+			keyBlock |= uint64(key[i] << 1)
+		}
+	}
+
+	sum := encrypt(newKeySchedule(keyBlock), 0, s, RoundsDefault)
+
+	out := make([]byte, 0, 13)
+	out = append(out, salt[:2]...)
+	out = appendBlock(out, sum)
+	return string(out), nil
+}
+
+func (c *crypter) Verify(hashedKey string, key []byte) error {
+	newHash, err := c.Generate(key, []byte(hashedKey))
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare([]byte(newHash), []byte(hashedKey)) != 1 {
+		return crypt.ErrKeyMismatch
+	}
+	return nil
+}
+
+func (c *crypter) Cost(hashedKey string) (int, error) { return RoundsDefault, nil }
+
+func (c *crypter) SetSalt(salt crypt.Salt) { c.Salt = salt }
+
+// SetParams only accepts the fixed number of rounds.
+func (c *crypter) SetParams(p crypt.Params) error {
+	if p.Rounds != 0 && p.Rounds != RoundsDefault {
+		return crypt.ErrSaltRounds
+	}
+	return nil
+}
+
+func (c *crypter) Params(hashedKey string) (crypt.Params, error) {
+	return crypt.Params{Rounds: RoundsDefault}, nil
+}
+
+// Generate hashes the whole key with a salt of the form "_<count><salt>",
+// where both the count and the salt are 4 characters.
+func (c *extendedCrypter) Generate(key, salt []byte) (string, error) {
+	if len(salt) == 0 {
+		return "", ErrGenerate
+	}
+	if !bytes.HasPrefix(salt, c.Salt.MagicPrefix) {
+		return "", crypt.ErrSaltPrefix
+	}
+	if len(salt) < 9 {
+		return "", crypt.ErrSaltFormat
+	}
+
+	count, ok := decodeInt(salt[1:5])
+	if !ok {
+		return "", crypt.ErrSaltFormat
+	}
+	if count < RoundsMinExtended || count > RoundsLimitExtended {
+		return "", crypt.ErrSaltRounds
+	}
+	s, ok := decodeInt(salt[5:9])
+	if !ok {
+		return "", crypt.ErrSaltFormat
+	}
+
+	// The key is folded 8 characters at a time: each chunk is XORed into the
+	// encryption of the key so far with itself.
+	var keyBlock uint64
+	for i := 0; i < 8; i++ {
+		keyBlock <<= 8
+		if i < len(key) {
+			keyBlock |= uint64(key[i] << 1)
+		}
+	}
+	ks := newKeySchedule(keyBlock)
+
+	rest := key[:0]
+	if len(key) > 8 {
+		rest = key[8:]
+	}
+	for len(rest) != 0 {
+		keyBlock = encrypt(ks, keyBlock, 0, 1)
+		for i := 0; i < 8 && len(rest) != 0; i++ {
+			keyBlock ^= uint64(rest[0]<<1) << uint(56-8*i)
+			rest = rest[1:]
+		}
+		ks = newKeySchedule(keyBlock)
+	}
+
+	sum := encrypt(ks, 0, s, count)
+
+	out := make([]byte, 0, 20)
+	out = append(out, salt[:9]...)
+	out = appendBlock(out, sum)
+	return string(out), nil
+}
+
+func (c *extendedCrypter) Verify(hashedKey string, key []byte) error {
+	newHash, err := c.Generate(key, []byte(hashedKey))
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare([]byte(newHash), []byte(hashedKey)) != 1 {
+		return crypt.ErrKeyMismatch
+	}
+	return nil
+}
+
+func (c *extendedCrypter) Cost(hashedKey string) (int, error) {
+	if len(hashedKey) < 5 || hashedKey[0] != MagicPrefixExtended[0] {
+		return 0, crypt.ErrSaltFormat
+	}
+	count, ok := decodeInt([]byte(hashedKey[1:5]))
+	if !ok {
+		return 0, crypt.ErrSaltFormat
+	}
+	return int(count), nil
+}
+
+func (c *extendedCrypter) SetSalt(salt crypt.Salt) { c.Salt = salt }
+
+// SetParams checks and sets the count, which is of no use as long as generating
+// new hashed keys is refused.
+func (c *extendedCrypter) SetParams(p crypt.Params) error {
+	if p.Rounds == 0 {
+		return nil
+	}
+	if p.Rounds < RoundsMinExtended || p.Rounds > RoundsLimitExtended {
+		return crypt.ErrSaltRounds
+	}
+	c.Salt.RoundsDefault = p.Rounds
+	return nil
+}
+
+func (c *extendedCrypter) Params(hashedKey string) (crypt.Params, error) {
+	count, err := c.Cost(hashedKey)
+	return crypt.Params{Rounds: count}, err
+}
+
+// decodeInt decodes the little-endian crypt Base64 of b.
+func decodeInt(b []byte) (uint32, bool) {
+	var v uint32
+	for i := len(b) - 1; i >= 0; i-- {
+		d := bytes.IndexByte([]byte(alphabet), b[i])
+		if d == -1 {
+			return 0, false
+		}
+		v = v<<6 | uint32(d)
+	}
+	return v, true
+}
+
+// appendBlock appends the 64 bits of v as 11 characters, most significant
+// first.
+func appendBlock(dst []byte, v uint64) []byte {
+	for i := 0; i < 10; i++ {
+		dst = append(dst, alphabet[(v>>uint(58-6*i))&0x3f])
+	}
+	return append(dst, alphabet[(v<<2)&0x3f])
+}
@@ -0,0 +1,104 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package des_crypt
+
+import (
+	"crypto/des"
+	"encoding/binary"
+	"testing"
+
+	"trident.li/go/osutil-crypt/common"
+)
+
+func TestEncrypt(t *testing.T) {
+	data := []struct{ key, block uint64 }{
+		{0x133457799bbcdff1, 0x0123456789abcdef},
+		{0x0e329232ea6d0d73, 0x8787878787878787},
+		{0, 0},
+	}
+	for i, d := range data {
+		var key, block, want [8]byte
+		binary.BigEndian.PutUint64(key[:], d.key)
+		binary.BigEndian.PutUint64(block[:], d.block)
+		c, err := des.NewCipher(key[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.Encrypt(want[:], block[:])
+
+		if got := encrypt(newKeySchedule(d.key), d.block, 0, 1); got != binary.BigEndian.Uint64(want[:]) {
+			t.Errorf("Test %d failed\nExpected: %x, got: %x", i, want, got)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	data := []struct {
+		crypter crypt.Crypter
+		salt    []byte
+		key     []byte
+		out     string
+		cost    int
+	}{
+		{New(), []byte("aa"), []byte("test"), "aaqPiZY5xR5l.", 25},
+		{New(), []byte(".."), []byte(""), "..X8NBuQ4l6uQ", 25},
+		{New(), []byte("abJnggxhB/yWI"), []byte("password"), "abJnggxhB/yWI", 25},
+		{New(), []byte("zz"), []byte("longpassword_more"), "zzSu2QW7SNyD2", 25},
+		{NewExtended(), []byte("_J9..abcd"), []byte("test"), "_J9..abcdx/cgK8gMibw", 725},
+		{NewExtended(), []byte("_J9..abcd"), []byte(""), "_J9..abcdoj0PMidvoVc", 725},
+		{
+			NewExtended(),
+			[]byte("_/...SALT"),
+			[]byte("a very long password indeed"),
+			"_/...SALTvJrUm09OCl2",
+			1,
+		},
+	}
+
+	for i, d := range data {
+		hash, err := d.crypter.Generate(d.key, d.salt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hash != d.out {
+			t.Errorf("Test %d failed\nExpected: %s, got: %s", i, d.out, hash)
+		}
+
+		cost, err := d.crypter.Cost(hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cost != d.cost {
+			t.Errorf("Test %d failed\nExpected: %d, got: %d", i, d.cost, cost)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	if err := New().Verify("aaqPiZY5xR5l.", []byte("test")); err != nil {
+		t.Error(err)
+	}
+	if err := New().Verify("aaqPiZY5xR5l.", []byte("tests")); err != crypt.ErrKeyMismatch {
+		t.Errorf("Expected ErrKeyMismatch, got: %v", err)
+	}
+	if err := NewExtended().Verify("_J9..abcdx/cgK8gMibw", []byte("test")); err != nil {
+		t.Error(err)
+	}
+	if err := NewExtended().Verify("_zzzzabcdx/cgK8gMibw", []byte("test")); err != crypt.ErrSaltRounds {
+		t.Errorf("Expected ErrSaltRounds, got: %v", err)
+	}
+	if err := NewExtended().SetParams(crypt.Params{Rounds: RoundsLimitExtended + 1}); err != crypt.ErrSaltRounds {
+		t.Errorf("Expected ErrSaltRounds, got: %v", err)
+	}
+
+	if _, err := New().Generate([]byte("test"), nil); err != ErrGenerate {
+		t.Errorf("Expected ErrGenerate, got: %v", err)
+	}
+	if _, err := NewExtended().Generate([]byte("test"), nil); err != ErrGenerate {
+		t.Errorf("Expected ErrGenerate, got: %v", err)
+	}
+}
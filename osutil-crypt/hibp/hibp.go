@@ -0,0 +1,237 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+// Package hibp checks passwords against the Pwned Passwords of Have I Been
+// Pwned, the passwords found in data breaches.
+//
+// Only the first 5 characters of the SHA-1 digest of a password leave the
+// host, with the range API, and the answer is padded; the check can also be
+// done offline, on a download of the dataset.
+//
+// The description of the range API can be found here:
+// https://haveibeenpwned.com/API/v3#PwnedPasswords
+package hibp
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"trident.li/go/osutil-crypt/strength"
+)
+
+// DefaultURL is the range API, to which the prefix of the digest is appended.
+const DefaultURL = "https://api.pwnedpasswords.com/range/"
+
+// defaultClient makes the requests when the Checker has no client, with a
+// timeout so that setting a password can not block on the range API.
+var defaultClient = &http.Client{Timeout: 10 * time.Second}
+
+// ErrPwned is returned by Check when the password was found in data breaches.
+var ErrPwned = errors.New("hibp: password found in data breaches")
+
+// Checker checks passwords against the Pwned Passwords. Its zero value uses
+// the range API.
+type Checker struct {
+	// URL is the range API; DefaultURL if empty.
+	URL string
+
+	// Client makes the requests; if nil, a client with a timeout of 10
+	// seconds.
+	Client *http.Client
+
+	// Dataset is a download of the Pwned Passwords with SHA-1 digests, used
+	// instead of the range API if not empty. It is either a file ordered by
+	// hash, with lines "<digest>:<count>", or a directory with a file
+	// "<prefix>.txt" per prefix, as the answers of the range API.
+	Dataset string
+
+	// MinCount is the number of breaches from which Check rejects a
+	// password; 1 if it is zero.
+	MinCount int
+}
+
+var _ strength.QualityChecker = (*Checker)(nil)
+
+// Check returns ErrPwned if the password was found in at least MinCount data
+// breaches. The user is not used.
+func (c *Checker) Check(user, password string) error {
+	return c.CheckContext(context.Background(), user, password)
+}
+
+// CheckContext is as Check, with a context for the request to the range API.
+func (c *Checker) CheckContext(ctx context.Context, user, password string) error {
+	n, err := c.CountContext(ctx, password)
+	if err != nil {
+		return err
+	}
+	min := c.MinCount
+	if min < 1 {
+		min = 1
+	}
+	if n >= min {
+		return ErrPwned
+	}
+	return nil
+}
+
+// Count returns the number of data breaches in which the password was found.
+func (c *Checker) Count(password string) (int, error) {
+	return c.CountContext(context.Background(), password)
+}
+
+// CountContext is as Count, with a context for the request to the range API.
+func (c *Checker) CountContext(ctx context.Context, password string) (int, error) {
+	sum := sha1.Sum([]byte(password))
+	digest := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := digest[:5], digest[5:]
+
+	if c.Dataset == "" {
+		return c.countRange(ctx, prefix, suffix)
+	}
+
+	fi, err := os.Stat(c.Dataset)
+	if err != nil {
+		return 0, err
+	}
+	if fi.IsDir() {
+		f, err := os.Open(filepath.Join(c.Dataset, prefix+".txt"))
+		if err != nil {
+			return 0, err
+		}
+		defer f.Close()
+		return findSuffix(f, suffix)
+	}
+	return searchFile(c.Dataset, digest)
+}
+
+// countRange asks the range API for the digests starting with the prefix.
+func (c *Checker) countRange(ctx context.Context, prefix, suffix string) (int, error) {
+	url := c.URL
+	if url == "" {
+		url = DefaultURL
+	}
+	client := c.Client
+	if client == nil {
+		client = defaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url+prefix, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Add-Padding", "true")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("hibp: range API: %s", resp.Status)
+	}
+	return findSuffix(resp.Body, suffix)
+}
+
+// findSuffix returns the count of the suffix, or the digest, in lines
+// "<suffix>:<count>". The padding lines have a count of zero.
+func findSuffix(r io.Reader, suffix string) (int, error) {
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		i := strings.IndexByte(line, ':')
+		if i == -1 || !strings.EqualFold(line[:i], suffix) {
+			continue
+		}
+		return parseCount(line)
+	}
+	return 0, s.Err()
+}
+
+// parseCount returns the count of a line "<digest>:<count>".
+func parseCount(line string) (int, error) {
+	n, err := strconv.Atoi(line[strings.IndexByte(line, ':')+1:])
+	if err != nil {
+		return 0, fmt.Errorf("hibp: invalid line: %q", line)
+	}
+	return n, nil
+}
+
+// searchFile does a binary search of the digest in a file ordered by hash.
+func searchFile(name, digest string) (int, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	// The line of the digest, if any, starts in [lo, hi), and lo is the start
+	// of a line.
+	lo, hi := int64(0), fi.Size()
+	for hi-lo > 4096 {
+		mid := lo + (hi-lo)/2
+		line, start, err := lineAfter(f, mid)
+		if err != nil {
+			return 0, err
+		}
+		if start >= hi {
+			hi = mid + 1
+			continue
+		}
+
+		key := line
+		if i := strings.IndexByte(line, ':'); i != -1 {
+			key = line[:i]
+		}
+		switch key = strings.ToUpper(key); {
+		case key == digest:
+			return parseCount(line)
+		case key < digest:
+			lo = start
+		default:
+			hi = start
+		}
+	}
+
+	if _, err = f.Seek(lo, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return findSuffix(io.LimitReader(f, hi-lo+4096), digest)
+}
+
+// lineAfter returns the first line which starts after the offset, and where
+// it starts; at the end of the file, the line is empty.
+func lineAfter(f *os.File, offset int64) (string, int64, error) {
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return "", 0, err
+	}
+	r := bufio.NewReader(f)
+	skipped, err := r.ReadString('\n')
+	if err == io.EOF {
+		return "", offset + int64(len(skipped)), nil
+	} else if err != nil {
+		return "", 0, err
+	}
+	line, err := r.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", 0, err
+	}
+	return strings.TrimSpace(line), offset + int64(len(skipped)), nil
+}
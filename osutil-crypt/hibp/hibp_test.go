@@ -0,0 +1,156 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package hibp
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// digest returns the SHA-1 digest of the password, as in the dataset.
+func digest(password string) string {
+	sum := sha1.Sum([]byte(password))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+// dataset returns the lines of a dataset with many passwords, ordered by hash;
+// the password "pwned<i>" has the count i+1.
+func dataset() []string {
+	var lines []string
+	for i := 0; i < 2000; i++ {
+		lines = append(lines, fmt.Sprintf("%s:%d", digest(fmt.Sprint("pwned", i)), i+1))
+	}
+	lines = append(lines, digest("password")+":9659365")
+	sort.Strings(lines)
+	return lines
+}
+
+var data = []struct {
+	password string
+	count    int
+}{
+	{"password", 9659365},
+	{"pwned0", 1},
+	{"pwned1999", 2000},
+	{"pwned777", 778},
+	{"not pwned", 0},
+	{"", 0},
+}
+
+func testChecker(t *testing.T, c *Checker) {
+	for i, d := range data {
+		n, err := c.Count(d.password)
+		if err != nil {
+			t.Fatalf("Test %d failed: %v", i, err)
+		}
+		if n != d.count {
+			t.Errorf("Test %d failed\nExpected: %d, got: %d", i, d.count, n)
+		}
+	}
+
+	if err := c.Check("user", "password"); err != ErrPwned {
+		t.Errorf("Expected ErrPwned, got: %v", err)
+	}
+	if err := c.Check("user", "not pwned"); err != nil {
+		t.Error(err)
+	}
+	c.MinCount = 1000
+	if err := c.Check("user", "pwned777"); err != nil {
+		t.Error(err)
+	}
+	c.MinCount = 0
+}
+
+func TestRange(t *testing.T) {
+	lines := dataset()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Add-Padding") != "true" {
+			t.Error("Expected padding")
+		}
+		prefix := strings.TrimPrefix(r.URL.Path, "/range/")
+		if len(prefix) != 5 {
+			http.Error(w, "bad prefix", http.StatusBadRequest)
+			return
+		}
+		for _, line := range lines {
+			if strings.HasPrefix(line, prefix) {
+				fmt.Fprintf(w, "%s\r\n", line[5:])
+			}
+		}
+		fmt.Fprint(w, "0000000000000000000000000000000000A:0\r\n")
+	}))
+	defer ts.Close()
+
+	testChecker(t, &Checker{URL: ts.URL + "/range/"})
+
+	c := &Checker{URL: ts.URL + "/other/"}
+	if _, err := c.Count("password"); err == nil {
+		t.Error("Expected an error for a bad status")
+	}
+}
+
+func TestRangeContext(t *testing.T) {
+	stall := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-stall
+	}))
+	defer ts.Close()
+	defer close(stall)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	c := &Checker{URL: ts.URL + "/range/"}
+	if err := c.CheckContext(ctx, "user", "password"); err == nil {
+		t.Error("Expected an error for a stalled request")
+	}
+}
+
+func TestDatasetFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "pwned-passwords-sha1-ordered-by-hash.txt")
+	if err := os.WriteFile(name, []byte(strings.Join(dataset(), "\r\n")+"\r\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	testChecker(t, &Checker{Dataset: name})
+}
+
+func TestDatasetDir(t *testing.T) {
+	dir := t.TempDir()
+	files := make(map[string][]string)
+	for _, line := range dataset() {
+		files[line[:5]] = append(files[line[:5]], line[5:])
+	}
+	for prefix, lines := range files {
+		err := os.WriteFile(filepath.Join(dir, prefix+".txt"), []byte(strings.Join(lines, "\r\n")), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := &Checker{Dataset: dir}
+	for i, d := range data {
+		n, err := c.Count(d.password)
+		if os.IsNotExist(err) && d.count == 0 {
+			continue // No digest with its prefix.
+		}
+		if err != nil {
+			t.Fatalf("Test %d failed: %v", i, err)
+		}
+		if n != d.count {
+			t.Errorf("Test %d failed\nExpected: %d, got: %d", i, d.count, n)
+		}
+	}
+}
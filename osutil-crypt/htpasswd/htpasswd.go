@@ -0,0 +1,279 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+// Package htpasswd reads, verifies against and updates the password files of
+// the Apache HTTP server, as created by htpasswd(1).
+//
+// The hashed keys can be bcrypt, APR1, SHA ("{SHA}" followed by the Base64 of
+// the SHA-1 digest, with no salt), and MD5-crypt, SHA256-crypt and
+// SHA512-crypt, which Apache verifies through crypt(3) on Unix.
+//
+// Files are updated with Update, which takes an exclusive lock on the file
+// "<name>.lock" and replaces the file atomically; so reading it needs no lock.
+package htpasswd
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"trident.li/go/osutil-crypt/apr1_crypt"
+	"trident.li/go/osutil-crypt/bcrypt_crypt"
+	"trident.li/go/osutil-crypt/common"
+	"trident.li/go/osutil-crypt/md5_crypt"
+	"trident.li/go/osutil-crypt/sha256_crypt"
+	"trident.li/go/osutil-crypt/sha512_crypt"
+)
+
+var (
+	ErrUser        = errors.New("htpasswd: user not found")
+	ErrUserName    = errors.New("htpasswd: invalid user name")
+	ErrUnsupported = errors.New("htpasswd: unsupported hashed key")
+)
+
+// line is a line of the file: an entry or, if user is empty, any other line.
+type line struct {
+	user, hash string
+	raw        string
+}
+
+// File represents the content of a password file.
+type File struct {
+	lines []line
+}
+
+// Read reads the password file with the given name.
+func Read(name string) (*File, error) {
+	b, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(b)
+}
+
+// Parse parses the content of a password file. The lines which are not entries,
+// i.e. comments, are kept as they are.
+func Parse(b []byte) (*File, error) {
+	f := new(File)
+	s := bufio.NewScanner(bytes.NewReader(b))
+	for s.Scan() {
+		text := strings.TrimSuffix(s.Text(), "\r")
+		if i := strings.IndexByte(text, ':'); i > 0 && !strings.HasPrefix(text, "#") {
+			f.lines = append(f.lines, line{user: text[:i], hash: text[i+1:]})
+		} else {
+			f.lines = append(f.lines, line{raw: text})
+		}
+	}
+	return f, s.Err()
+}
+
+// Bytes returns the content of the password file.
+func (f *File) Bytes() []byte {
+	var buf bytes.Buffer
+	for _, l := range f.lines {
+		if l.user == "" {
+			buf.WriteString(l.raw)
+		} else {
+			buf.WriteString(l.user)
+			buf.WriteByte(':')
+			buf.WriteString(l.hash)
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// Users returns the users of the file, in its order.
+func (f *File) Users() []string {
+	var users []string
+	for _, l := range f.lines {
+		if l.user != "" {
+			users = append(users, l.user)
+		}
+	}
+	return users
+}
+
+// Get returns the hashed key of the user.
+func (f *File) Get(user string) (string, bool) {
+	for _, l := range f.lines {
+		if l.user != "" && l.user == user {
+			return l.hash, true
+		}
+	}
+	return "", false
+}
+
+// Verify compares the hashed key of the user with the password. It returns
+// ErrUser if the user is not found, and crypt.ErrKeyMismatch if the password
+// is wrong.
+func (f *File) Verify(user, password string) error {
+	hash, ok := f.Get(user)
+	if !ok {
+		return ErrUser
+	}
+	c, err := crypterFromHash(hash)
+	if err != nil {
+		return err
+	}
+	return c.Verify(hash, []byte(password))
+}
+
+// Set sets the hashed key of the user, adding the user at the end of the file
+// if it is not found.
+func (f *File) Set(user, hash string) error {
+	if user == "" || strings.ContainsAny(user, ":\r\n") || strings.HasPrefix(user, "#") ||
+		strings.ContainsAny(hash, "\r\n") {
+		return ErrUserName
+	}
+	for i := range f.lines {
+		if f.lines[i].user == user {
+			f.lines[i].hash = hash
+			return nil
+		}
+	}
+	f.lines = append(f.lines, line{user: user, hash: hash})
+	return nil
+}
+
+// SetPassword hashes the password with the crypter and sets it for the user.
+// A nil crypter means bcrypt with the prefix "$2y$", as "htpasswd -B" writes.
+func (f *File) SetPassword(user, password string, c crypt.Crypter) error {
+	if c == nil {
+		c = newBcrypt()
+	}
+	hash, err := c.Generate([]byte(password), nil)
+	if err != nil {
+		return err
+	}
+	return f.Set(user, hash)
+}
+
+// Delete removes the user, reporting whether it was found.
+func (f *File) Delete(user string) bool {
+	for i := range f.lines {
+		if f.lines[i].user != "" && f.lines[i].user == user {
+			f.lines = append(f.lines[:i], f.lines[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Update locks the password file with the given name, calls fn with its
+// content, and then replaces the file with the changes made by fn, unless fn
+// returns an error. A file which does not exist is created with the given
+// permissions.
+func Update(name string, perm os.FileMode, fn func(f *File) error) error {
+	unlock, err := lock(name + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	f, err := Read(name)
+	if os.IsNotExist(err) {
+		f, err = new(File), nil
+	} else if err == nil {
+		var fi os.FileInfo
+		if fi, err = os.Stat(name); err == nil {
+			perm = fi.Mode().Perm()
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if err = fn(f); err != nil {
+		return err
+	}
+	return writeFile(name, f.Bytes(), perm)
+}
+
+// writeFile writes the data to a temporary file in the same directory, and
+// renames it to the given name.
+func writeFile(name string, data []byte, perm os.FileMode) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(name), "."+filepath.Base(name)+".")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // Fails once renamed.
+
+	if _, err = tmp.Write(data); err == nil {
+		if err = tmp.Chmod(perm); err == nil {
+			err = tmp.Sync()
+		}
+	}
+	if err1 := tmp.Close(); err == nil {
+		err = err1
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
+
+// newBcrypt returns a bcrypt crypter generating hashed keys with the prefix
+// "$2y$", since the crypt_blowfish bundled by older builds of apr-util does not
+// know "$2b$".
+func newBcrypt() crypt.Crypter {
+	c := bcrypt_crypt.New()
+	c.SetSalt(crypt.Salt{
+		MagicPrefix:   []byte("$2y$"),
+		SaltLenMin:    bcrypt_crypt.SaltLenMin,
+		SaltLenMax:    bcrypt_crypt.SaltLenMax,
+		RoundsDefault: bcrypt_crypt.RoundsDefault,
+		RoundsMin:     bcrypt_crypt.RoundsMin,
+		RoundsMax:     bcrypt_crypt.RoundsMax,
+	})
+	return c
+}
+
+// crypterFromHash returns the crypter of the hashed key. It returns a
+// *crypt.PolicyError if its crypt function is not allowed by
+// crypt.SetAllowedAlgorithms, where SHA is crypt.SHA1.
+func crypterFromHash(hash string) (crypt.Crypter, error) {
+	var c crypt.Crypter
+	var id crypt.Crypt
+
+	switch {
+	case isBcrypt(hash):
+		c, id = bcrypt_crypt.New(), crypt.BCRYPT
+	case strings.HasPrefix(hash, apr1_crypt.MagicPrefix):
+		c, id = apr1_crypt.New(), crypt.APR1
+	case strings.HasPrefix(hash, MagicPrefixSHA):
+		c, id = NewSHA(), crypt.SHA1
+	case strings.HasPrefix(hash, md5_crypt.MagicPrefix):
+		c, id = md5_crypt.New(), crypt.MD5
+	case strings.HasPrefix(hash, sha256_crypt.MagicPrefix):
+		c, id = sha256_crypt.New(), crypt.SHA256
+	case strings.HasPrefix(hash, sha512_crypt.MagicPrefix):
+		c, id = sha512_crypt.New(), crypt.SHA512
+	default:
+		return nil, ErrUnsupported
+	}
+
+	if !crypt.Allowed(id) {
+		return nil, &crypt.PolicyError{Crypt: id}
+	}
+	return c, nil
+}
+
+func isBcrypt(hash string) bool {
+	if strings.HasPrefix(hash, bcrypt_crypt.MagicPrefix) {
+		return true
+	}
+	for _, p := range bcrypt_crypt.Prefixes {
+		if strings.HasPrefix(hash, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,168 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package htpasswd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"trident.li/go/osutil-crypt/bcrypt_crypt"
+	"trident.li/go/osutil-crypt/common"
+)
+
+const content = `# Users of the site.
+alice:$apr1$12345678$/DpfgRGBHG8N0cbkmw0Fk/
+bob:$2y$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW
+
+carol:{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g=
+dave:abJnggxhB/yWI
+`
+
+func TestVerify(t *testing.T) {
+	f, err := Parse([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(f.Bytes()) != content {
+		t.Errorf("Expected:\n%s\ngot:\n%s", content, f.Bytes())
+	}
+	if users := f.Users(); !reflect.DeepEqual(users, []string{"alice", "bob", "carol", "dave"}) {
+		t.Errorf("Unexpected users: %v", users)
+	}
+
+	data := []struct {
+		user, password string
+		err            error
+	}{
+		{"alice", "Lorem ipsum dolor sit amet", nil},
+		{"alice", "Lorem ipsum", crypt.ErrKeyMismatch},
+		{"bob", "U*U", nil},
+		{"bob", "U*V", crypt.ErrKeyMismatch},
+		{"carol", "password", nil},
+		{"carol", "Password", crypt.ErrKeyMismatch},
+		{"dave", "password", ErrUnsupported},
+		{"eve", "password", ErrUser},
+		{"# Users of the site.", "", ErrUser},
+	}
+
+	for i, d := range data {
+		if err := f.Verify(d.user, d.password); err != d.err {
+			t.Errorf("Test %d failed\nExpected: %v, got: %v", i, d.err, err)
+		}
+	}
+}
+
+func TestVerifyAllowed(t *testing.T) {
+	f, err := Parse([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer crypt.SetAllowedAlgorithms()
+
+	crypt.SetAllowedAlgorithms(crypt.BCRYPT)
+	if err = f.Verify("bob", "U*U"); err != nil {
+		t.Error(err)
+	}
+	for _, d := range []struct {
+		user  string
+		crypt crypt.Crypt
+	}{
+		{"alice", crypt.APR1},
+		{"carol", crypt.SHA1},
+	} {
+		err = f.Verify(d.user, "password")
+		if e, ok := err.(*crypt.PolicyError); !ok || e.Crypt != d.crypt {
+			t.Errorf("Expected a policy error for %v, got: %v", d.crypt, err)
+		}
+	}
+
+	crypt.SetAllowedAlgorithms(crypt.BCRYPT, crypt.SHA1)
+	if err = f.Verify("carol", "password"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	name := filepath.Join(t.TempDir(), ".htpasswd")
+	c := bcrypt_crypt.New()
+	c.SetParams(crypt.Params{Rounds: bcrypt_crypt.RoundsMin})
+
+	err := Update(name, 0640, func(f *File) error {
+		if err := f.SetPassword("alice", "secret", c); err != nil {
+			return err
+		}
+		return f.SetPassword("bob", "secret", NewSHA())
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi, err := os.Stat(name); err != nil || fi.Mode().Perm() != 0640 {
+		t.Errorf("Unexpected file: %v, %v", fi, err)
+	}
+
+	// Concurrent updates must not be lost.
+	var wg sync.WaitGroup
+	for _, user := range []string{"carol", "dave", "eve", "frank"} {
+		wg.Add(1)
+		go func(user string) {
+			defer wg.Done()
+			err := Update(name, 0600, func(f *File) error {
+				return f.SetPassword(user, "secret", NewSHA())
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}(user)
+	}
+	wg.Wait()
+
+	err = Update(name, 0600, func(f *File) error {
+		if !f.Delete("bob") {
+			t.Error("Expected to delete bob")
+		}
+		if err := f.Set("x:y", "hash"); err != ErrUserName {
+			t.Errorf("Expected ErrUserName, got: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := Read(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if users := f.Users(); len(users) != 5 || users[0] != "alice" {
+		t.Errorf("Unexpected users: %v", users)
+	}
+	for _, user := range f.Users() {
+		if err = f.Verify(user, "secret"); err != nil {
+			t.Errorf("%s: %v", user, err)
+		}
+	}
+	if fi, err := os.Stat(name); err != nil || fi.Mode().Perm() != 0640 {
+		t.Errorf("Unexpected file: %v, %v", fi, err)
+	}
+}
+
+func TestSetPasswordBcrypt(t *testing.T) {
+	f := new(File)
+	if err := f.SetPassword("alice", "secret", nil); err != nil {
+		t.Fatal(err)
+	}
+	if hash, _ := f.Get("alice"); !strings.HasPrefix(hash, "$2y$10$") {
+		t.Errorf("Expected prefix: $2y$10$, got: %s", hash)
+	}
+	if err := f.Verify("alice", "secret"); err != nil {
+		t.Error(err)
+	}
+}
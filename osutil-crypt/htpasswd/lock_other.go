@@ -0,0 +1,22 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly && !illumos
+// +build !linux,!darwin,!freebsd,!netbsd,!openbsd,!dragonfly,!illumos
+
+package htpasswd
+
+import "os"
+
+// lock creates the lock file without locking it, since flock(2) is not
+// available; the updates are still atomic, but concurrent ones can be lost.
+func lock(name string) (func(), error) {
+	f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return func() { f.Close() }, nil
+}
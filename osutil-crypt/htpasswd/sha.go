@@ -0,0 +1,59 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package htpasswd
+
+import (
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base64"
+	"strings"
+
+	"trident.li/go/osutil-crypt/common"
+)
+
+const MagicPrefixSHA = "{SHA}"
+
+type shaCrypter struct{ Salt crypt.Salt }
+
+// NewSHA returns a new crypt.Crypter computing the "{SHA}" hashes of htpasswd.
+// They have no salt nor rounds, so they should only be used for compatibility.
+func NewSHA() crypt.Crypter {
+	return &shaCrypter{crypt.Salt{MagicPrefix: []byte(MagicPrefixSHA), RoundsDefault: 1}}
+}
+
+// Generate hashes the key. The salt is ignored.
+func (c *shaCrypter) Generate(key, salt []byte) (string, error) {
+	sum := sha1.Sum(key)
+	return MagicPrefixSHA + base64.StdEncoding.EncodeToString(sum[:]), nil
+}
+
+func (c *shaCrypter) Verify(hashedKey string, key []byte) error {
+	if !strings.HasPrefix(hashedKey, MagicPrefixSHA) {
+		return crypt.ErrSaltPrefix
+	}
+	newHash, _ := c.Generate(key, nil)
+	if subtle.ConstantTimeCompare([]byte(newHash), []byte(hashedKey)) != 1 {
+		return crypt.ErrKeyMismatch
+	}
+	return nil
+}
+
+func (c *shaCrypter) Cost(hashedKey string) (int, error) { return 1, nil }
+
+func (c *shaCrypter) SetSalt(salt crypt.Salt) { c.Salt = salt }
+
+// SetParams only accepts the fixed number of rounds.
+func (c *shaCrypter) SetParams(p crypt.Params) error {
+	if p.Rounds != 0 && p.Rounds != 1 {
+		return crypt.ErrSaltRounds
+	}
+	return nil
+}
+
+func (c *shaCrypter) Params(hashedKey string) (crypt.Params, error) {
+	return crypt.Params{Rounds: 1}, nil
+}
@@ -12,6 +12,7 @@ package md5_crypt
 import (
 	"bytes"
 	"crypto/md5"
+	"crypto/subtle"
 
 	"trident.li/go/osutil-crypt/common"
 )
@@ -148,7 +149,7 @@ func (c *crypter) Verify(hashedKey string, key []byte) error {
 	if err != nil {
 		return err
 	}
-	if newHash != hashedKey {
+	if subtle.ConstantTimeCompare([]byte(newHash), []byte(hashedKey)) != 1 {
 		return crypt.ErrKeyMismatch
 	}
 	return nil
@@ -157,3 +158,15 @@ func (c *crypter) Verify(hashedKey string, key []byte) error {
 func (c *crypter) Cost(hashedKey string) (int, error) { return RoundsDefault, nil }
 
 func (c *crypter) SetSalt(salt crypt.Salt) { c.Salt = salt }
+
+// SetParams only accepts the fixed number of rounds.
+func (c *crypter) SetParams(p crypt.Params) error {
+	if p.Rounds != 0 && p.Rounds != RoundsDefault {
+		return crypt.ErrSaltRounds
+	}
+	return nil
+}
+
+func (c *crypter) Params(hashedKey string) (crypt.Params, error) {
+	return crypt.Params{Rounds: RoundsDefault}, nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+// Package nt_crypt implements the NT hash of Windows, as stored by Samba in
+// smbpasswd files and in the sambaNTPassword attribute of LDAP.
+//
+// The NT hash is the MD4 digest of the key in UTF-16LE, without any salt nor
+// rounds, so it has no magic prefix: its hashed keys are 32 hexadecimal
+// characters, in upper case as Samba writes them. It is only meant to keep
+// Samba in sync with the passwords of the system.
+package nt_crypt
+
+import (
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+	"unicode/utf16"
+
+	"golang.org/x/crypto/md4"
+
+	"trident.li/go/osutil-crypt/common"
+)
+
+const (
+	MagicPrefix   = "" // The NT hash has no magic prefix.
+	SaltLenMin    = 0
+	SaltLenMax    = 0
+	RoundsDefault = 1
+)
+
+type crypter struct{ Salt crypt.Salt }
+
+// New returns a new crypt.Crypter computing the NT hash.
+func New() crypt.Crypter {
+	return &crypter{
+		crypt.Salt{
+			MagicPrefix:   []byte(MagicPrefix),
+			SaltLenMin:    SaltLenMin,
+			SaltLenMax:    SaltLenMax,
+			RoundsDefault: RoundsDefault,
+		},
+	}
+}
+
+// Generate hashes the key, which is handled as UTF-8. The salt is ignored.
+func (c *crypter) Generate(key, salt []byte) (string, error) {
+	return strings.ToUpper(hex.EncodeToString(sum(key))), nil
+}
+
+// Verify compares the digests, so the hashed key can also be in lower case as
+// some tools write it.
+func (c *crypter) Verify(hashedKey string, key []byte) error {
+	hashed, err := hex.DecodeString(hashedKey)
+	if err != nil || len(hashed) != md4.Size {
+		return crypt.ErrSaltFormat
+	}
+	if subtle.ConstantTimeCompare(sum(key), hashed) != 1 {
+		return crypt.ErrKeyMismatch
+	}
+	return nil
+}
+
+func (c *crypter) Cost(hashedKey string) (int, error) { return RoundsDefault, nil }
+
+func (c *crypter) SetSalt(salt crypt.Salt) { c.Salt = salt }
+
+// SetParams only accepts the fixed number of rounds.
+func (c *crypter) SetParams(p crypt.Params) error {
+	if p.Rounds != 0 && p.Rounds != RoundsDefault {
+		return crypt.ErrSaltRounds
+	}
+	return nil
+}
+
+func (c *crypter) Params(hashedKey string) (crypt.Params, error) {
+	return crypt.Params{Rounds: RoundsDefault}, nil
+}
+
+// sum returns the MD4 digest of the key in UTF-16LE.
+func sum(key []byte) []byte {
+	u := utf16.Encode([]rune(string(key)))
+	b := make([]byte, 0, 2*len(u))
+	for _, r := range u {
+		b = append(b, byte(r), byte(r>>8))
+	}
+
+	h := md4.New()
+	h.Write(b)
+
+	// Clean sensitive data.
+	for i := range b {
+		b[i] = 0
+	}
+
+	return h.Sum(nil)
+}
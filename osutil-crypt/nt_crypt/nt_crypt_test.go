@@ -0,0 +1,66 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package nt_crypt
+
+import (
+	"strings"
+	"testing"
+
+	"trident.li/go/osutil-crypt/common"
+)
+
+var ntCrypt = New()
+
+func TestGenerate(t *testing.T) {
+	data := []struct {
+		key []byte
+		out string
+	}{
+		{[]byte(""), "31D6CFE0D16AE931B73C59D7E0C089C0"},
+		{[]byte("password"), "8846F7EAEE8FB117AD06BDD830B7586C"},
+		{[]byte("Pa$$w0rd"), "92937945B518814341DE3F726500D4FF"},
+		{[]byte("übermäßig"), "AAE8A4D2EE58C5ADD02CB325C809A6C2"},
+	}
+
+	for i, d := range data {
+		hash, err := ntCrypt.Generate(d.key, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hash != d.out {
+			t.Errorf("Test %d failed\nExpected: %s, got: %s", i, d.out, hash)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	data := [][]byte{
+		[]byte("password"),
+		[]byte("12345"),
+		[]byte("That's amazing! I've got the same combination on my luggage!"),
+		[]byte("And change the combination on my luggage!"),
+		[]byte("         random  spa  c    ing."),
+		[]byte("94ajflkvjzpe8u3&*j1k513KLJ&*()"),
+	}
+
+	for i, d := range data {
+		hash, err := ntCrypt.Generate(d, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = ntCrypt.Verify(hash, d); err != nil {
+			t.Errorf("Test %d failed: %s", i, d)
+		}
+		if err = ntCrypt.Verify(strings.ToLower(hash), d); err != nil {
+			t.Errorf("Test %d failed in lower case: %s", i, d)
+		}
+	}
+
+	if err := ntCrypt.Verify("8846F7EAEE8FB117AD06BDD830B7586C", []byte("Password")); err != crypt.ErrKeyMismatch {
+		t.Errorf("Expected ErrKeyMismatch, got: %v", err)
+	}
+}
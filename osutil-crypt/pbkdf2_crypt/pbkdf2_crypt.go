@@ -0,0 +1,179 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+// Package pbkdf2_crypt implements PBKDF2 password hashing with HMAC-SHA256 and
+// HMAC-SHA512, in the modular crypt format of Python's passlib.
+//
+// The specification for this algorithm can be found here:
+// https://www.rfc-editor.org/rfc/rfc8018
+package pbkdf2_crypt
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"hash"
+	"strconv"
+
+	"golang.org/x/crypto/pbkdf2"
+
+	"trident.li/go/osutil-crypt/common"
+)
+
+const (
+	MagicPrefixSHA256 = "$pbkdf2-sha256$"
+	MagicPrefixSHA512 = "$pbkdf2-sha512$"
+	SaltLenMin        = 0 // In bytes, before encoding.
+	SaltLenMax        = 16
+	RoundsMin         = 1
+	RoundsMax         = 1<<31 - 1
+
+	RoundsDefaultSHA256 = 29000
+	RoundsDefaultSHA512 = 25000
+)
+
+// ab64 is the "adapted Base64" of passlib: standard Base64 using "." instead
+// of "+", and without padding.
+var ab64 = base64.NewEncoding(
+	"ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789./",
+).WithPadding(base64.NoPadding)
+
+type crypter struct {
+	Salt crypt.Salt
+
+	hash func() hash.Hash
+	size int
+}
+
+// NewSHA256 returns a new crypt.Crypter computing PBKDF2 with HMAC-SHA256.
+func NewSHA256() crypt.Crypter {
+	return &crypter{
+		Salt: crypt.Salt{
+			MagicPrefix:   []byte(MagicPrefixSHA256),
+			SaltLenMin:    SaltLenMin,
+			SaltLenMax:    SaltLenMax,
+			RoundsDefault: RoundsDefaultSHA256,
+			RoundsMin:     RoundsMin,
+			RoundsMax:     RoundsMax,
+		},
+		hash: sha256.New,
+		size: sha256.Size,
+	}
+}
+
+// NewSHA512 returns a new crypt.Crypter computing PBKDF2 with HMAC-SHA512.
+func NewSHA512() crypt.Crypter {
+	return &crypter{
+		Salt: crypt.Salt{
+			MagicPrefix:   []byte(MagicPrefixSHA512),
+			SaltLenMin:    SaltLenMin,
+			SaltLenMax:    SaltLenMax,
+			RoundsDefault: RoundsDefaultSHA512,
+			RoundsMin:     RoundsMin,
+			RoundsMax:     RoundsMax,
+		},
+		hash: sha512.New,
+		size: sha512.Size,
+	}
+}
+
+// Generate hashes the key with a salt of the form
+// "$pbkdf2-<digest>$<rounds>$<salt>", where the salt is in adapted Base64.
+func (c *crypter) Generate(key, salt []byte) (string, error) {
+	if len(salt) == 0 {
+		salt = c.generateSalt()
+	}
+	if !bytes.HasPrefix(salt, c.Salt.MagicPrefix) {
+		return "", crypt.ErrSaltPrefix
+	}
+
+	saltToks := bytes.Split(salt, []byte{'$'})
+	if len(saltToks) < 4 {
+		return "", crypt.ErrSaltFormat
+	}
+
+	rounds, err := strconv.ParseInt(string(saltToks[2]), 10, 32)
+	if err != nil || rounds < RoundsMin {
+		return "", crypt.ErrSaltRounds
+	}
+
+	rawSalt, err := ab64.DecodeString(string(saltToks[3]))
+	if err != nil {
+		return "", crypt.ErrSaltFormat
+	}
+
+	sum := pbkdf2.Key(key, rawSalt, int(rounds), c.size, c.hash)
+
+	out := make([]byte, 0, len(salt)+ab64.EncodedLen(c.size)+1)
+	out = append(out, c.Salt.MagicPrefix...)
+	out = append(out, saltToks[2]...)
+	out = append(out, '$')
+	out = append(out, saltToks[3]...)
+	out = append(out, '$')
+	out = append(out, ab64.EncodeToString(sum)...)
+
+	// Clean sensitive data.
+	for i := range sum {
+		sum[i] = 0
+	}
+
+	return string(out), nil
+}
+
+func (c *crypter) Verify(hashedKey string, key []byte) error {
+	newHash, err := c.Generate(key, []byte(hashedKey))
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare([]byte(newHash), []byte(hashedKey)) != 1 {
+		return crypt.ErrKeyMismatch
+	}
+	return nil
+}
+
+func (c *crypter) Cost(hashedKey string) (int, error) {
+	saltToks := bytes.Split([]byte(hashedKey), []byte{'$'})
+	if len(saltToks) < 4 {
+		return 0, crypt.ErrSaltFormat
+	}
+	cost, err := strconv.ParseInt(string(saltToks[2]), 10, 0)
+	return int(cost), err
+}
+
+func (c *crypter) SetSalt(salt crypt.Salt) { c.Salt = salt }
+
+// SetParams sets the number of rounds.
+func (c *crypter) SetParams(p crypt.Params) error {
+	if p.Rounds == 0 {
+		return nil
+	}
+	if p.Rounds < RoundsMin || p.Rounds > RoundsMax {
+		return crypt.ErrSaltRounds
+	}
+	c.Salt.RoundsDefault = p.Rounds
+	return nil
+}
+
+func (c *crypter) Params(hashedKey string) (crypt.Params, error) {
+	rounds, err := c.Cost(hashedKey)
+	return crypt.Params{Rounds: rounds}, err
+}
+
+// generateSalt returns a random salt with the default number of rounds.
+func (c *crypter) generateSalt() []byte {
+	raw := make([]byte, c.Salt.SaltLenMax)
+	rand.Read(raw)
+
+	out := make([]byte, 0, len(c.Salt.MagicPrefix)+11+ab64.EncodedLen(len(raw)))
+	out = append(out, c.Salt.MagicPrefix...)
+	out = strconv.AppendInt(out, int64(c.Salt.RoundsDefault), 10)
+	out = append(out, '$')
+	out = append(out, ab64.EncodeToString(raw)...)
+	return out
+}
@@ -0,0 +1,125 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package pbkdf2_crypt
+
+import (
+	"testing"
+
+	"trident.li/go/osutil-crypt/common"
+)
+
+func TestGenerate(t *testing.T) {
+	data := []struct {
+		crypter crypt.Crypter
+		salt    []byte
+		key     []byte
+		out     string
+		cost    int
+	}{
+		{
+			NewSHA256(),
+			[]byte("$pbkdf2-sha256$6400$c2FsdHNhbHRzYWx0c2FsdA"),
+			[]byte("password"),
+			"$pbkdf2-sha256$6400$c2FsdHNhbHRzYWx0c2FsdA$" +
+				"SjugYu7ZPm/2vgGhvpJO5OhTJOckhO77BQUaCnLK3cs",
+			6400,
+		},
+		{
+			NewSHA256(),
+			[]byte("$pbkdf2-sha256$1000$AAECAwQFBgcICQoLDA0ODw"),
+			[]byte(""),
+			"$pbkdf2-sha256$1000$AAECAwQFBgcICQoLDA0ODw$" +
+				"xbMBsf1hvO1j8AZCojBOxnRRn7182DxLyD2v4XQ/mFU",
+			1000,
+		},
+		{
+			NewSHA512(),
+			[]byte("$pbkdf2-sha512$6400$c2FsdHNhbHRzYWx0c2FsdA"),
+			[]byte("password"),
+			"$pbkdf2-sha512$6400$c2FsdHNhbHRzYWx0c2FsdA$" +
+				"ju27oQSyN6c2TmlhXUho7bSCyOV5/Pl6ilQuhArwaAPDYIFO.oCDd2YbiVdk" +
+				"sT9jYSY0GCeRnx4RLV.CQtxlJQ",
+			6400,
+		},
+		{
+			NewSHA512(),
+			[]byte("$pbkdf2-sha512$25000$yMnKy8zNzs/Q0dLT1NXW1w"),
+			[]byte("Hello world!"),
+			"$pbkdf2-sha512$25000$yMnKy8zNzs/Q0dLT1NXW1w$" +
+				"ZBNZ5M.k6PhZwNKA.ayOcKrdJjVHINc7Lntss7bZtyDzMPXAbgGL.gB.a/73" +
+				"Fo12xzSK9wLgSg8Bj4z.T0e6Sg",
+			25000,
+		},
+	}
+
+	for i, d := range data {
+		hash, err := d.crypter.Generate(d.key, d.salt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hash != d.out {
+			t.Errorf("Test %d failed\nExpected: %s, got: %s", i, d.out, hash)
+		}
+
+		cost, err := d.crypter.Cost(hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cost != d.cost {
+			t.Errorf("Test %d failed\nExpected: %d, got: %d", i, d.cost, cost)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	data := [][]byte{
+		[]byte("password"),
+		[]byte("12345"),
+		[]byte("That's amazing! I've got the same combination on my luggage!"),
+		[]byte("And change the combination on my luggage!"),
+		[]byte("         random  spa  c    ing."),
+		[]byte("94ajflkvjzpe8u3&*j1k513KLJ&*()"),
+	}
+	for _, c := range []crypt.Crypter{NewSHA256(), NewSHA512()} {
+		for i, d := range data {
+			hash, err := c.Generate(d, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = c.Verify(hash, d); err != nil {
+				t.Errorf("Test %d failed: %s", i, d)
+			}
+		}
+	}
+}
+
+func TestParams(t *testing.T) {
+	c := NewSHA256()
+	want := crypt.Params{Rounds: 1000}
+
+	if err := c.SetParams(crypt.Params{Rounds: -1}); err != crypt.ErrSaltRounds {
+		t.Errorf("Expected ErrSaltRounds, got: %v", err)
+	}
+	if err := c.SetParams(want); err != nil {
+		t.Fatal(err)
+	}
+
+	hash, err := c.Generate([]byte("password"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params, err := c.Params(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params != want {
+		t.Errorf("Expected: %+v, got: %+v", want, params)
+	}
+	if err = c.Verify(hash, []byte("password")); err != nil {
+		t.Error(err)
+	}
+}
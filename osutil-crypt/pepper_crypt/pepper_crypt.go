@@ -0,0 +1,181 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+// Package pepper_crypt wraps a crypter with a pepper: a secret key kept by the
+// application, i.e. in a KMS, and not in the database of the hashed keys.
+//
+// The key is replaced by its HMAC-SHA256 with the pepper, in standard Base64,
+// before being hashed by the wrapped crypter. So a leak of the database alone
+// does not allow to crack the hashed keys.
+//
+// The hashed keys are of the form "$pepper$<key id>" followed by the hashed key
+// of the wrapped crypter, where the key id names the pepper so that it can be
+// rotated. They are not recognized by NewFromHash, since the pepper is needed.
+// For the same reason crypt.NeedsRehash always reports them as needing a
+// rehash; use NeedsRehash of this package instead, which checks the key id and
+// the hashed key of the wrapped crypter.
+package pepper_crypt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"trident.li/go/osutil-crypt/common"
+)
+
+const MagicPrefix = "$pepper$"
+
+var (
+	// ErrKeyID is returned when a hashed key was created with another pepper.
+	ErrKeyID = errors.New("pepper_crypt: hashed key uses another pepper")
+
+	// ErrInvalidKeyID is returned by New for an empty key id or one with '$'.
+	ErrInvalidKeyID = errors.New("pepper_crypt: invalid key id")
+
+	// ErrPepper is returned by New for an empty pepper.
+	ErrPepper = errors.New("pepper_crypt: empty pepper")
+)
+
+type crypter struct {
+	c      crypt.Crypter
+	prefix string
+	pepper []byte
+}
+
+// New returns a new crypt.Crypter which applies the pepper, named by the key
+// id, before hashing with c. The key id can not be empty nor contain '$', the
+// pepper can not be empty, and the hashed keys of c have to start with '$'.
+func New(c crypt.Crypter, keyID string, pepper []byte) (crypt.Crypter, error) {
+	if keyID == "" || strings.Contains(keyID, "$") {
+		return nil, ErrInvalidKeyID
+	}
+	if len(pepper) == 0 {
+		return nil, ErrPepper
+	}
+	return &crypter{
+		c:      c,
+		prefix: MagicPrefix + keyID,
+		pepper: append([]byte(nil), pepper...),
+	}, nil
+}
+
+// KeyID returns the key id of the pepper used to create the given hashed key,
+// so the crypter with that pepper can be chosen.
+func KeyID(hashedKey string) (string, bool) {
+	if !strings.HasPrefix(hashedKey, MagicPrefix) {
+		return "", false
+	}
+	id := hashedKey[len(MagicPrefix):]
+	i := strings.IndexByte(id, '$')
+	if i < 1 {
+		return "", false
+	}
+	return id[:i], true
+}
+
+// NeedsRehash reports whether the hashed key should be replaced by a new one,
+// because it is not peppered, its pepper is not the one named by the key id,
+// or the hashed key of the wrapped crypter does not follow the policy, as
+// crypt.NeedsRehash reports.
+func NeedsRehash(hashedKey, keyID string, policy crypt.Policy) bool {
+	id, ok := KeyID(hashedKey)
+	if !ok || id != keyID {
+		return true
+	}
+	return crypt.NeedsRehash(hashedKey[len(MagicPrefix)+len(id):], policy)
+}
+
+// Generate hashes the key with the wrapped crypter, after applying the pepper.
+// The salt is the one of the wrapped crypter, which can have the prefix of
+// this crypter.
+func (c *crypter) Generate(key, salt []byte) (string, error) {
+	if len(salt) != 0 && strings.HasPrefix(string(salt), MagicPrefix) {
+		inner, err := c.inner(string(salt))
+		if err != nil {
+			return "", err
+		}
+		salt = []byte(inner)
+	}
+
+	mac := c.mac(key)
+	hash, err := c.c.Generate(mac, salt)
+
+	// Clean sensitive data.
+	for i := range mac {
+		mac[i] = 0
+	}
+
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(hash, "$") {
+		return "", crypt.ErrSaltPrefix
+	}
+	return c.prefix + hash, nil
+}
+
+func (c *crypter) Verify(hashedKey string, key []byte) error {
+	inner, err := c.inner(hashedKey)
+	if err != nil {
+		return err
+	}
+
+	mac := c.mac(key)
+	err = c.c.Verify(inner, mac)
+
+	// Clean sensitive data.
+	for i := range mac {
+		mac[i] = 0
+	}
+
+	return err
+}
+
+func (c *crypter) Cost(hashedKey string) (int, error) {
+	inner, err := c.inner(hashedKey)
+	if err != nil {
+		return 0, err
+	}
+	return c.c.Cost(inner)
+}
+
+func (c *crypter) SetSalt(salt crypt.Salt) { c.c.SetSalt(salt) }
+
+func (c *crypter) SetParams(p crypt.Params) error { return c.c.SetParams(p) }
+
+func (c *crypter) Params(hashedKey string) (crypt.Params, error) {
+	inner, err := c.inner(hashedKey)
+	if err != nil {
+		return crypt.Params{}, err
+	}
+	return c.c.Params(inner)
+}
+
+// inner returns the hashed key of the wrapped crypter.
+func (c *crypter) inner(hashedKey string) (string, error) {
+	if !strings.HasPrefix(hashedKey, MagicPrefix) {
+		return "", crypt.ErrSaltPrefix
+	}
+	if !strings.HasPrefix(hashedKey, c.prefix+"$") {
+		return "", ErrKeyID
+	}
+	return hashedKey[len(c.prefix):], nil
+}
+
+// mac returns the HMAC of the key with the pepper, in standard Base64 so that
+// it has no NUL bytes for the crypters which stop at them.
+func (c *crypter) mac(key []byte) []byte {
+	h := hmac.New(sha256.New, c.pepper)
+	h.Write(key)
+	sum := h.Sum(nil)
+
+	out := make([]byte, base64.StdEncoding.EncodedLen(len(sum)))
+	base64.StdEncoding.Encode(out, sum)
+	return out
+}
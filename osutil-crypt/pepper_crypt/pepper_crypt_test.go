@@ -0,0 +1,167 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package pepper_crypt
+
+import (
+	"strings"
+	"testing"
+
+	"trident.li/go/osutil-crypt/bcrypt_crypt"
+	"trident.li/go/osutil-crypt/common"
+	"trident.li/go/osutil-crypt/sha512_crypt"
+)
+
+var pepperCrypt = mustNew(sha512_crypt.New(), "k1", []byte("a secret pepper"))
+
+func mustNew(c crypt.Crypter, keyID string, pepper []byte) crypt.Crypter {
+	c, err := New(c, keyID, pepper)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func TestNew(t *testing.T) {
+	data := []struct {
+		keyID  string
+		pepper []byte
+		err    error
+	}{
+		{"k1", []byte("a secret pepper"), nil},
+		{"", []byte("a secret pepper"), ErrInvalidKeyID},
+		{"k$1", []byte("a secret pepper"), ErrInvalidKeyID},
+		{"k1", nil, ErrPepper},
+		{"k1", []byte{}, ErrPepper},
+	}
+
+	for i, d := range data {
+		if _, err := New(sha512_crypt.New(), d.keyID, d.pepper); err != d.err {
+			t.Errorf("Test %d failed\nExpected: %v, got: %v", i, d.err, err)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	data := []struct {
+		salt []byte
+		key  []byte
+		out  string
+		cost int
+	}{
+		{
+			[]byte("$6$saltstring"),
+			[]byte("Hello world!"),
+			"$pepper$k1$6$saltstring$L055JvL5OqvPE8fpo8Br8uYm5tnqlmUeZKw0U1qYWcezHSD6jJSKuM21jq1lD7sDtsxlZ9.8Kkp0gnS3SbYlb0",
+			sha512_crypt.RoundsDefault,
+		},
+		{
+			[]byte("$pepper$k1$6$rounds=10000$saltstring"),
+			[]byte("Hello world!"),
+			"$pepper$k1$6$rounds=10000$saltstring$/VO2we3a1sFHOWP6kD5FD0G0.QxaNGt1B3wJobJw.fkN86PHbbYY9Geky6WJlALyjUwjiGG3H/hoDe9XsTXi90",
+			10000,
+		},
+	}
+
+	for i, d := range data {
+		hash, err := pepperCrypt.Generate(d.key, d.salt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hash != d.out {
+			t.Errorf("Test %d failed\nExpected: %s, got: %s", i, d.out, hash)
+		}
+
+		cost, err := pepperCrypt.Cost(hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cost != d.cost {
+			t.Errorf("Test %d failed\nExpected: %d, got: %d", i, d.cost, cost)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	data := [][]byte{
+		[]byte("password"),
+		[]byte("12345"),
+		[]byte("That's amazing! I've got the same combination on my luggage!"),
+		[]byte("And change the combination on my luggage!"),
+		[]byte("         random  spa  c    ing."),
+		[]byte("94ajflkvjzpe8u3&*j1k513KLJ&*()"),
+	}
+
+	for i, d := range data {
+		hash, err := pepperCrypt.Generate(d, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = pepperCrypt.Verify(hash, d); err != nil {
+			t.Errorf("Test %d failed: %s", i, d)
+		}
+	}
+
+	hash, err := pepperCrypt.Generate([]byte("password"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := mustNew(sha512_crypt.New(), "k1", []byte("another pepper"))
+	if err = other.Verify(hash, []byte("password")); err != crypt.ErrKeyMismatch {
+		t.Errorf("Expected ErrKeyMismatch, got: %v", err)
+	}
+	other = mustNew(sha512_crypt.New(), "k2", []byte("a secret pepper"))
+	if err = other.Verify(hash, []byte("password")); err != ErrKeyID {
+		t.Errorf("Expected ErrKeyID, got: %v", err)
+	}
+	if err = pepperCrypt.Verify(strings.TrimPrefix(hash, "$pepper$k1"), []byte("password")); err != crypt.ErrSaltPrefix {
+		t.Errorf("Expected ErrSaltPrefix, got: %v", err)
+	}
+
+	if id, ok := KeyID(hash); !ok || id != "k1" {
+		t.Errorf("Expected key id k1, got: %q, %v", id, ok)
+	}
+}
+
+func TestLongKey(t *testing.T) {
+	// bcrypt only uses 72 bytes of the key, but the HMAC covers all of it.
+	c := mustNew(bcrypt_crypt.New(), "k1", []byte("a secret pepper"))
+	c.SetParams(crypt.Params{Rounds: bcrypt_crypt.RoundsMin})
+
+	key := []byte(strings.Repeat("x", 80))
+	hash, err := c.Generate(key, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key[79] = 'y'
+	if err = c.Verify(hash, key); err != crypt.ErrKeyMismatch {
+		t.Errorf("Expected ErrKeyMismatch, got: %v", err)
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	crypt.RegisterCrypt(crypt.SHA512, sha512_crypt.New, sha512_crypt.MagicPrefix)
+	defer crypt.UnregisterCrypt(crypt.SHA512)
+
+	policy := crypt.Policy{Crypt: crypt.SHA512, Params: crypt.Params{Rounds: 10000}}
+	data := []struct {
+		hash  string
+		keyID string
+		out   bool
+	}{
+		{"$pepper$k1$6$rounds=10000$saltstring$/VO2we3a1sFHOWP6kD5FD0G0.QxaNGt1B3wJobJw.fkN86PHbbYY9Geky6WJlALyjUwjiGG3H/hoDe9XsTXi90", "k1", false},
+		{"$pepper$k1$6$rounds=10000$saltstring$/VO2we3a1sFHOWP6kD5FD0G0.QxaNGt1B3wJobJw.fkN86PHbbYY9Geky6WJlALyjUwjiGG3H/hoDe9XsTXi90", "k2", true},
+		{"$pepper$k1$6$saltstring$L055JvL5OqvPE8fpo8Br8uYm5tnqlmUeZKw0U1qYWcezHSD6jJSKuM21jq1lD7sDtsxlZ9.8Kkp0gnS3SbYlb0", "k1", true},
+		{"$6$rounds=10000$saltstring$", "k1", true},
+		{"$pepper$k1", "k1", true},
+	}
+
+	for i, d := range data {
+		if out := NeedsRehash(d.hash, d.keyID, policy); out != d.out {
+			t.Errorf("Test %d failed\nExpected: %t, got: %t", i, d.out, out)
+		}
+	}
+}
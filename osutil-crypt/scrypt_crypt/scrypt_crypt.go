@@ -0,0 +1,246 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+// Package scrypt_crypt implements Colin Percival's scrypt password hashing
+// algorithm, in the "$7$" encoding used by libxcrypt and OpenBSD.
+//
+// The specification for this algorithm can be found here:
+// https://www.tarsnap.com/scrypt/scrypt.pdf
+//
+// As explained in the package common, the parameters of a hashed key are
+// bounded: the memory, 128*r*(N+p) bytes, by MemoryMax, and the blocks mixed
+// over the p lanes, 128*N*r*p bytes, by WorkMax, about 4 seconds of a CPU. So
+// N = 2^20 with r = 8 and p = 1, or N = 2^15 with r = 32 and p = 8, is the
+// most a hashed key can ask.
+package scrypt_crypt
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+
+	"golang.org/x/crypto/scrypt"
+
+	"trident.li/go/osutil-crypt/common"
+)
+
+const (
+	MagicPrefix   = "$7$"
+	SaltLenMin    = 1
+	SaltLenMax    = 43
+	RoundsMin     = 1 // The cost N, as a base-2 logarithm.
+	RoundsMax     = 31
+	RoundsDefault = 14
+
+	BlockSizeDefault   = 32 // r
+	ParallelismDefault = 1  // p
+
+	MemoryMax = 2 << 30 // In bytes.
+	WorkMax   = 1 << 30 // In bytes, for all the lanes.
+
+	KeyLen = 32
+)
+
+const alphabet = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+type crypter struct {
+	Salt crypt.Salt
+
+	r, p int
+}
+
+// New returns a new crypt.Crypter computing the scrypt password hashing.
+func New() crypt.Crypter {
+	return &crypter{
+		Salt: crypt.Salt{
+			MagicPrefix:   []byte(MagicPrefix),
+			SaltLenMin:    SaltLenMin,
+			SaltLenMax:    SaltLenMax,
+			RoundsDefault: RoundsDefault,
+			RoundsMin:     RoundsMin,
+			RoundsMax:     RoundsMax,
+		},
+		r: BlockSizeDefault,
+		p: ParallelismDefault,
+	}
+}
+
+// Generate hashes the key with a salt of the form "$7$<N><r><p><salt>", where
+// N is one character holding its base-2 logarithm, r and p five characters
+// each, all of them in little-endian crypt Base64.
+func (c *crypter) Generate(key, salt []byte) (string, error) {
+	if len(salt) == 0 {
+		salt = c.generateSalt(c.Salt.RoundsDefault, c.r, c.p)
+	}
+	if !bytes.HasPrefix(salt, c.Salt.MagicPrefix) {
+		return "", crypt.ErrSaltPrefix
+	}
+
+	setting := salt[len(c.Salt.MagicPrefix):]
+	if i := bytes.IndexByte(setting, '$'); i != -1 {
+		setting = setting[:i]
+	}
+	if len(setting) < 11+SaltLenMin {
+		return "", crypt.ErrSaltFormat
+	}
+
+	nLog2, r, p, err := parseParams(setting[:11])
+	if err != nil {
+		return "", err
+	}
+	salt = setting[11:]
+	if len(salt) > SaltLenMax {
+		salt = salt[:SaltLenMax]
+	}
+
+	// The salt is used as it is written, without decoding it.
+	sum, err := scrypt.Key(key, salt, 1<<uint(nLog2), r, p, KeyLen)
+	if err != nil {
+		return "", crypt.ErrSaltRounds
+	}
+
+	out := make([]byte, 0, len(c.Salt.MagicPrefix)+12+len(salt)+43)
+	out = append(out, c.Salt.MagicPrefix...)
+	out = append(out, setting[:11]...)
+	out = append(out, salt...)
+	out = append(out, '$')
+	out = append(out, crypt.Base64_24Bit(sum)...)
+
+	// Clean sensitive data.
+	for i := range sum {
+		sum[i] = 0
+	}
+
+	return string(out), nil
+}
+
+func (c *crypter) Verify(hashedKey string, key []byte) error {
+	newHash, err := c.Generate(key, []byte(hashedKey))
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare([]byte(newHash), []byte(hashedKey)) != 1 {
+		return crypt.ErrKeyMismatch
+	}
+	return nil
+}
+
+// Cost returns the base-2 logarithm of the cost N used to create the given
+// hashed key.
+func (c *crypter) Cost(hashedKey string) (int, error) {
+	if len(hashedKey) < len(MagicPrefix)+11 {
+		return 0, crypt.ErrSaltFormat
+	}
+	nLog2, _, _, err := parseParams([]byte(hashedKey[len(MagicPrefix):]))
+	return nLog2, err
+}
+
+func (c *crypter) SetSalt(salt crypt.Salt) { c.Salt = salt }
+
+// SetParams sets the base-2 logarithm of N (as rounds), r (as block size) and p
+// (as parallelism).
+func (c *crypter) SetParams(p crypt.Params) error {
+	nLog2, r, par := c.Salt.RoundsDefault, c.r, c.p
+	if p.Rounds != 0 {
+		nLog2 = p.Rounds
+	}
+	if p.BlockSize != 0 {
+		r = p.BlockSize
+	}
+	if p.Parallelism != 0 {
+		par = p.Parallelism
+	}
+
+	// scrypt requires r*p < 2^30, which also keeps both in their 30 bits.
+	if nLog2 < RoundsMin || nLog2 > RoundsMax ||
+		r < 1 || par < 1 || int64(r)*int64(par) >= 1<<30 ||
+		exceedsLimits(nLog2, r, par) {
+		return crypt.ErrSaltRounds
+	}
+
+	c.Salt.RoundsDefault = nLog2
+	c.r, c.p = r, par
+	return nil
+}
+
+func (c *crypter) Params(hashedKey string) (crypt.Params, error) {
+	if len(hashedKey) < len(MagicPrefix)+11 {
+		return crypt.Params{}, crypt.ErrSaltFormat
+	}
+	nLog2, r, p, err := parseParams([]byte(hashedKey[len(MagicPrefix):]))
+	if err != nil {
+		return crypt.Params{}, err
+	}
+	return crypt.Params{Rounds: nLog2, BlockSize: r, Parallelism: p}, nil
+}
+
+// generateSalt returns a random salt with the given parameters.
+func (c *crypter) generateSalt(nLog2, r, p int) []byte {
+	if nLog2 < c.Salt.RoundsMin {
+		nLog2 = c.Salt.RoundsMin
+	} else if nLog2 > c.Salt.RoundsMax {
+		nLog2 = c.Salt.RoundsMax
+	}
+
+	raw := make([]byte, c.Salt.SaltLenMax*6/8)
+	rand.Read(raw)
+
+	out := make([]byte, 0, len(c.Salt.MagicPrefix)+11+c.Salt.SaltLenMax)
+	out = append(out, c.Salt.MagicPrefix...)
+	out = append(out, alphabet[nLog2])
+	out = appendUint30(out, uint32(r))
+	out = appendUint30(out, uint32(p))
+	out = append(out, crypt.Base64_24Bit(raw)...)
+	return out
+}
+
+// parseParams decodes the 11 characters holding N, r and p.
+func parseParams(b []byte) (nLog2, r, p int, err error) {
+	if len(b) < 11 {
+		return 0, 0, 0, crypt.ErrSaltFormat
+	}
+
+	nLog2 = bytes.IndexByte([]byte(alphabet), b[0])
+	if nLog2 < RoundsMin || nLog2 > RoundsMax {
+		return 0, 0, 0, crypt.ErrSaltRounds
+	}
+
+	var v [2]uint32
+	for i := range v {
+		for j := 0; j < 5; j++ {
+			d := bytes.IndexByte([]byte(alphabet), b[1+i*5+j])
+			if d == -1 {
+				return 0, 0, 0, crypt.ErrSaltFormat
+			}
+			v[i] |= uint32(d) << uint(6*j)
+		}
+	}
+	if v[0] == 0 || v[1] == 0 {
+		return 0, 0, 0, crypt.ErrSaltFormat
+	}
+	if exceedsLimits(nLog2, int(v[0]), int(v[1])) {
+		return 0, 0, 0, crypt.ErrSaltRounds
+	}
+
+	return nLog2, int(v[0]), int(v[1]), nil
+}
+
+// exceedsLimits reports whether scrypt would allocate more than MemoryMax
+// bytes or mix more than WorkMax bytes with the given parameters.
+func exceedsLimits(nLog2, r, p int) bool {
+	n := int64(1) << uint(nLog2)
+	return 128*int64(r)*(n+int64(p)) > MemoryMax ||
+		int64(r)*int64(p) > WorkMax/128/n
+}
+
+// appendUint30 appends the 30 lower bits of v as five characters.
+func appendUint30(dst []byte, v uint32) []byte {
+	for i := 0; i < 5; i++ {
+		dst = append(dst, alphabet[v&0x3f])
+		v >>= 6
+	}
+	return dst
+}
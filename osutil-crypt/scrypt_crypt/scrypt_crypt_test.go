@@ -0,0 +1,142 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package scrypt_crypt
+
+import (
+	"testing"
+
+	"trident.li/go/osutil-crypt/common"
+)
+
+var scryptCrypt = New()
+
+func TestGenerate(t *testing.T) {
+	data := []struct {
+		salt []byte
+		key  []byte
+		out  string
+		cost int
+	}{
+		{
+			[]byte("$7$C6..../....SodiumChloride"),
+			[]byte(""),
+			"$7$C6..../....SodiumChloride$" +
+				"rstazfmyrYQxDGNTkWA0DIuyHi5YTtliav1u8qLNN8C",
+			14,
+		},
+		{
+			[]byte("$7$C6..../....SodiumChloride"),
+			[]byte("pleaseletmein"),
+			"$7$C6..../....SodiumChloride$" +
+				"kBGj9fHznVYFQMEn/qDCfrDevf9YDtcDdKvEqHJLV8D",
+			14,
+		},
+		{
+			[]byte("$7$9/..../..../salt$"),
+			[]byte("password"),
+			"$7$9/..../..../salt$" +
+				"3A6DwMeQlGUAYPgTLtRAwe0KfJH2Bd5AZyjzSIoaTj1",
+			11,
+		},
+		{
+			[]byte("$7$CU..../....abcdefgh"),
+			[]byte("test"),
+			"$7$CU..../....abcdefgh$" +
+				"QoGc8orTFLWfnVR53sl429LSCetd3m9DhEv64ri9OG7",
+			14,
+		},
+	}
+
+	for i, d := range data {
+		hash, err := scryptCrypt.Generate(d.key, d.salt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hash != d.out {
+			t.Errorf("Test %d failed\nExpected: %s, got: %s", i, d.out, hash)
+		}
+
+		cost, err := scryptCrypt.Cost(hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cost != d.cost {
+			t.Errorf("Test %d failed\nExpected: %d, got: %d", i, d.cost, cost)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	data := [][]byte{
+		[]byte("password"),
+		[]byte("12345"),
+		[]byte("That's amazing! I've got the same combination on my luggage!"),
+		[]byte("And change the combination on my luggage!"),
+		[]byte("         random  spa  c    ing."),
+		[]byte("94ajflkvjzpe8u3&*j1k513KLJ&*()"),
+	}
+	for i, d := range data {
+		hash, err := scryptCrypt.Generate(d, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = scryptCrypt.Verify(hash, d); err != nil {
+			t.Errorf("Test %d failed: %s", i, d)
+		}
+	}
+}
+
+func TestParams(t *testing.T) {
+	c := New()
+	want := crypt.Params{Rounds: 10, BlockSize: 8, Parallelism: 2}
+
+	if err := c.SetParams(crypt.Params{BlockSize: 1 << 15, Parallelism: 1 << 15}); err != crypt.ErrSaltRounds {
+		t.Errorf("Expected ErrSaltRounds, got: %v", err)
+	}
+	if err := c.SetParams(want); err != nil {
+		t.Fatal(err)
+	}
+
+	hash, err := c.Generate([]byte("password"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params, err := c.Params(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params != want {
+		t.Errorf("Expected: %+v, got: %+v", want, params)
+	}
+	if err = c.Verify(hash, []byte("password")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestVerifyLimits(t *testing.T) {
+	for i, d := range []string{
+		"$7$T/..../....saltstring$",
+		"$7$Ezzzzz/....saltstring$",
+		"$7$A/....zzzzzsaltstring$",
+		"$7$I7..../....saltstring$",
+		"$7$Cz/...D....saltstring$",
+	} {
+		if err := New().Verify(d, []byte("x")); err != crypt.ErrSaltRounds {
+			t.Errorf("Test %d failed\nExpected: %v, got: %v", i, crypt.ErrSaltRounds, err)
+		}
+	}
+
+	if err := New().SetParams(crypt.Params{Rounds: 20, BlockSize: 1024}); err != crypt.ErrSaltRounds {
+		t.Errorf("Expected ErrSaltRounds, got: %v", err)
+	}
+	if err := New().SetParams(crypt.Params{Rounds: 15, BlockSize: 32, Parallelism: 9}); err != crypt.ErrSaltRounds {
+		t.Errorf("Expected ErrSaltRounds, got: %v", err)
+	}
+	if err := New().SetParams(crypt.Params{Rounds: 15, BlockSize: 32, Parallelism: 8}); err != nil {
+		t.Error(err)
+	}
+}
@@ -15,6 +15,7 @@ package sha256_crypt
 import (
 	"bytes"
 	"crypto/sha256"
+	"crypto/subtle"
 	"strconv"
 
 	"trident.li/go/osutil-crypt/common"
@@ -31,12 +32,16 @@ const (
 
 var _rounds = []byte("rounds=")
 
-type crypter struct{ Salt crypt.Salt }
+type crypter struct {
+	Salt crypt.Salt
+
+	rounds int
+}
 
 // New returns a new crypt.Crypter computing the SHA256-crypt password hashing.
 func New() crypt.Crypter {
 	return &crypter{
-		crypt.Salt{
+		Salt: crypt.Salt{
 			MagicPrefix:   []byte(MagicPrefix),
 			SaltLenMin:    SaltLenMin,
 			SaltLenMax:    SaltLenMax,
@@ -44,6 +49,7 @@ func New() crypt.Crypter {
 			RoundsMin:     RoundsMin,
 			RoundsMax:     RoundsMax,
 		},
+		rounds: RoundsDefault,
 	}
 }
 
@@ -52,7 +58,7 @@ func (c *crypter) Generate(key, salt []byte) (string, error) {
 	var isRoundsDef bool
 
 	if len(salt) == 0 {
-		salt = c.Salt.GenerateWRounds(SaltLenMax, RoundsDefault)
+		salt = c.Salt.GenerateWRounds(SaltLenMax, c.rounds)
 	}
 	if !bytes.HasPrefix(salt, c.Salt.MagicPrefix) {
 		return "", crypt.ErrSaltPrefix
@@ -64,6 +70,9 @@ func (c *crypter) Generate(key, salt []byte) (string, error) {
 	}
 
 	if bytes.HasPrefix(saltToks[2], _rounds) {
+		if len(saltToks) < 4 {
+			return "", crypt.ErrSaltFormat
+		}
 		isRoundsDef = true
 		pr, err := strconv.ParseInt(string(saltToks[2][7:]), 10, 32)
 		if err != nil {
@@ -213,7 +222,7 @@ func (c *crypter) Verify(hashedKey string, key []byte) error {
 	if err != nil {
 		return err
 	}
-	if newHash != hashedKey {
+	if subtle.ConstantTimeCompare([]byte(newHash), []byte(hashedKey)) != 1 {
 		return crypt.ErrKeyMismatch
 	}
 	return nil
@@ -234,3 +243,20 @@ func (c *crypter) Cost(hashedKey string) (int, error) {
 }
 
 func (c *crypter) SetSalt(salt crypt.Salt) { c.Salt = salt }
+
+// SetParams sets the number of rounds.
+func (c *crypter) SetParams(p crypt.Params) error {
+	if p.Rounds == 0 {
+		return nil
+	}
+	if p.Rounds < RoundsMin || p.Rounds > RoundsMax {
+		return crypt.ErrSaltRounds
+	}
+	c.rounds = p.Rounds
+	return nil
+}
+
+func (c *crypter) Params(hashedKey string) (crypt.Params, error) {
+	rounds, err := c.Cost(hashedKey)
+	return crypt.Params{Rounds: rounds}, err
+}
@@ -7,7 +7,11 @@
 
 package sha512_crypt
 
-import "testing"
+import (
+	"testing"
+
+	"trident.li/go/osutil-crypt/common"
+)
 
 var sha512Crypt = New()
 
@@ -109,3 +113,30 @@ func TestVerify(t *testing.T) {
 		}
 	}
 }
+
+func TestParams(t *testing.T) {
+	c := New()
+	want := crypt.Params{Rounds: 10000}
+
+	if err := c.SetParams(crypt.Params{Rounds: RoundsMax + 1}); err != crypt.ErrSaltRounds {
+		t.Errorf("Expected ErrSaltRounds, got: %v", err)
+	}
+	if err := c.SetParams(want); err != nil {
+		t.Fatal(err)
+	}
+
+	hash, err := c.Generate([]byte("password"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params, err := c.Params(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params != want {
+		t.Errorf("Expected: %+v, got: %+v", want, params)
+	}
+	if err = c.Verify(hash, []byte("password")); err != nil {
+		t.Error(err)
+	}
+}
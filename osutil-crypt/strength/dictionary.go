@@ -0,0 +1,131 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package strength
+
+import (
+	_ "embed"
+	"strings"
+	"sync"
+)
+
+// The frequency lists of zxcvbn, whose rank is the line number.
+var (
+	//go:embed data/passwords.txt
+	passwordsList string
+	//go:embed data/english.txt
+	englishList string
+	//go:embed data/surnames.txt
+	surnamesList string
+	//go:embed data/male_names.txt
+	maleNamesList string
+	//go:embed data/female_names.txt
+	femaleNamesList string
+)
+
+// Kinds of the words of the dictionary.
+const (
+	kindPassword = iota
+	kindEnglish
+	kindName
+	kindUser
+)
+
+var (
+	frequencyOnce sync.Once
+	frequency     map[string]entry
+)
+
+// frequencyRanks returns the entries of the frequency lists, built on first
+// use. A word in several lists keeps its lowest rank.
+func frequencyRanks() map[string]entry {
+	frequencyOnce.Do(func() {
+		frequency = make(map[string]entry, 60000)
+		for _, l := range []struct {
+			words string
+			kind  int
+		}{
+			{passwordsList, kindPassword},
+			{englishList, kindEnglish},
+			{surnamesList, kindName},
+			{maleNamesList, kindName},
+			{femaleNamesList, kindName},
+		} {
+			for w, rank := range ranks(strings.Fields(l.words)) {
+				if e, ok := frequency[w]; !ok || rank < e.rank {
+					frequency[w] = entry{rank: rank, kind: l.kind}
+				}
+			}
+		}
+	})
+	return frequency
+}
+
+func ranks(words []string) map[string]int {
+	m := make(map[string]int, len(words))
+	for i, w := range words {
+		w = strings.ToLower(w)
+		if _, ok := m[w]; !ok {
+			m[w] = i + 1
+		}
+	}
+	return m
+}
+
+// entry is a word found in the dictionary.
+type entry struct {
+	rank int
+	kind int
+}
+
+func (e entry) warning() string {
+	switch {
+	case e.kind == kindUser:
+		return "Personal information is easy to guess"
+	case e.kind == kindEnglish:
+		return "A word by itself is easy to guess"
+	case e.kind == kindName:
+		return "Names and surnames are easy to guess"
+	case e.rank <= 20:
+		return "This is a top-20 common password"
+	case e.rank <= 100:
+		return "This is a top-100 common password"
+	}
+	return "This is similar to a commonly used password"
+}
+
+// dictionary holds the frequency lists and the user inputs, which are the
+// first words tried.
+type dictionary struct {
+	user map[string]int
+}
+
+func newDictionary(userInputs []string) *dictionary {
+	var words []string
+	for _, in := range userInputs {
+		in = strings.ToLower(in)
+		words = append(words, in)
+		// Also the parts of an e-mail address or a full name.
+		words = append(words, strings.FieldsFunc(in, func(r rune) bool {
+			return strings.ContainsRune(" @._-+", r)
+		})...)
+	}
+	for i := 0; i < len(words); i++ {
+		if len(words[i]) < 3 {
+			words = append(words[:i], words[i+1:]...)
+			i--
+		}
+	}
+	return &dictionary{user: ranks(words)}
+}
+
+func (d *dictionary) lookup(word string) (entry, bool) {
+	if rank, ok := d.user[word]; ok {
+		return entry{rank: rank, kind: kindUser}, true
+	}
+	e, ok := frequencyRanks()[word]
+	return e, ok
+}
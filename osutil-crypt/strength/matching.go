@@ -0,0 +1,283 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package strength
+
+import (
+	"math"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Minimum guesses of a match, so that a pattern is not cheaper than brute
+// force of a few characters.
+const (
+	minGuessesSingle = 10
+	minGuessesMulti  = 50
+)
+
+// Keyboard rows, for the keyboard patterns.
+var keyboardRows = []string{
+	"`1234567890-=",
+	"qwertyuiop[]\\",
+	"asdfghjkl;'",
+	"zxcvbnm,./",
+}
+
+// l33t maps the substitutions of letters which are undone before looking up
+// the dictionary. The digit 1 is tried both as "i" and as "l".
+var l33t = map[rune]rune{
+	'4': 'a', '@': 'a', '3': 'e', '1': 'i', '!': 'i', '|': 'l',
+	'0': 'o', '$': 's', '5': 's', '7': 't', '+': 't', '2': 'z',
+}
+
+// match is a pattern found at runes[i:j+1].
+type match struct {
+	i, j    int
+	guesses float64
+	warning string
+}
+
+func newMatch(i, j int, guesses float64, warning string) match {
+	min := float64(minGuessesMulti)
+	if i == j {
+		min = minGuessesSingle
+	}
+	return match{i, j, math.Max(guesses, min), warning}
+}
+
+func findMatches(runes []rune, dict *dictionary) []match {
+	var matches []match
+	matches = append(matches, dictionaryMatches(runes, dict)...)
+	matches = append(matches, sequenceMatches(runes)...)
+	matches = append(matches, repeatMatches(runes, dict)...)
+	matches = append(matches, keyboardMatches(runes)...)
+	matches = append(matches, yearMatches(runes)...)
+	return matches
+}
+
+// dictionaryMatches finds the words of the dictionary, also reversed and with
+// l33t substitutions.
+func dictionaryMatches(runes []rune, dict *dictionary) []match {
+	lower := []rune(strings.ToLower(string(runes)))
+	if len(lower) != len(runes) {
+		lower = make([]rune, len(runes))
+		for i, r := range runes {
+			lower[i] = unicode.ToLower(r)
+		}
+	}
+
+	var matches []match
+	find := func(s []rune, factor float64, reversed bool) {
+		n := len(s)
+		for i := 0; i < n; i++ {
+			for j := i; j < n; j++ {
+				e, ok := dict.lookup(string(s[i : j+1]))
+				if !ok {
+					continue
+				}
+				mi, mj := i, j
+				if reversed {
+					mi, mj = n-1-j, n-1-i
+				}
+				g := float64(e.rank) * factor * uppercaseVariations(runes[mi:mj+1])
+				matches = append(matches, newMatch(mi, mj, g, e.warning()))
+			}
+		}
+	}
+
+	find(lower, 1, false)
+
+	for _, one := range []rune{'i', 'l'} {
+		unleet := make([]rune, len(lower))
+		changed := false
+		for i, r := range lower {
+			if s, ok := l33t[r]; ok {
+				if r == '1' {
+					s = one
+				}
+				unleet[i], changed = s, true
+			} else {
+				unleet[i] = r
+			}
+		}
+		if changed {
+			find(unleet, 2, false)
+		}
+	}
+
+	reversed := make([]rune, len(lower))
+	for i, r := range lower {
+		reversed[len(lower)-1-i] = r
+	}
+	find(reversed, 2, true)
+
+	return matches
+}
+
+// uppercaseVariations returns the number of ways to capitalize the word which
+// give as many uppercase letters as it has.
+func uppercaseVariations(word []rune) float64 {
+	var upper, lower int
+	for _, r := range word {
+		if unicode.IsUpper(r) {
+			upper++
+		} else if unicode.IsLower(r) {
+			lower++
+		}
+	}
+	switch {
+	case upper == 0:
+		return 1
+	case lower == 0, upper == 1 && (unicode.IsUpper(word[0]) || unicode.IsUpper(word[len(word)-1])):
+		return 2
+	}
+
+	var v float64
+	for k := 1; k <= upper && k <= lower; k++ {
+		v += binomial(upper+lower, k)
+	}
+	return v
+}
+
+func binomial(n, k int) float64 {
+	r := 1.0
+	for i := 1; i <= k; i++ {
+		r = r * float64(n-k+i) / float64(i)
+	}
+	return r
+}
+
+// sequenceMatches finds runs of 3 or more characters in order, like "abc" or
+// "9876".
+func sequenceMatches(runes []rune) []match {
+	var matches []match
+	for i := 0; i+2 < len(runes); {
+		delta := runes[i+1] - runes[i]
+		if delta != 1 && delta != -1 {
+			i++
+			continue
+		}
+		j := i + 1
+		for j+1 < len(runes) && runes[j+1]-runes[j] == delta {
+			j++
+		}
+		if j-i >= 2 {
+			base := 26.0
+			switch {
+			case strings.ContainsRune("aAzZ019", runes[i]):
+				base = 4
+			case unicode.IsDigit(runes[i]):
+				base = 10
+			}
+			g := base * float64(j-i+1)
+			if delta < 0 {
+				g *= 2
+			}
+			matches = append(matches, newMatch(i, j, g, "Sequences like abc or 6543 are easy to guess"))
+		}
+		i = j
+	}
+	return matches
+}
+
+// repeatMatches finds a block of characters repeated several times, like
+// "aaa" or "abcabc". Only the longest repeats of blocks which are not repeats
+// themselves are kept, since the guesses of each block are estimated again.
+func repeatMatches(runes []rune, dict *dictionary) []match {
+	var matches []match
+	for i := 0; i < len(runes); i++ {
+		for size := 1; i+2*size <= len(runes); size++ {
+			if i >= size && string(runes[i-size:i]) == string(runes[i:i+size]) ||
+				hasPeriod(runes[i:i+size]) {
+				continue
+			}
+			count := 1
+			for i+(count+1)*size <= len(runes) &&
+				string(runes[i+count*size:i+(count+1)*size]) == string(runes[i:i+size]) {
+				count++
+			}
+			if count < 2 || size == 1 && count < 3 {
+				continue
+			}
+			block, _ := mostGuessable(runes[i:i+size], dict)
+			warning := "Repeats like \"abcabc\" are only slightly harder to guess than \"abc\""
+			if size == 1 {
+				warning = "Repeats like \"aaa\" are easy to guess"
+			}
+			matches = append(matches, newMatch(i, i+count*size-1, block*float64(count), warning))
+		}
+	}
+	return matches
+}
+
+// hasPeriod reports whether the block is a shorter block repeated.
+func hasPeriod(block []rune) bool {
+	for p := 1; p <= len(block)/2; p++ {
+		if len(block)%p == 0 && string(block[p:]) == string(block[:len(block)-p]) {
+			return true
+		}
+	}
+	return false
+}
+
+// keyboardMatches finds runs of 3 or more adjacent keys of a row, in either
+// direction, like "qwerty" or "lkjh".
+func keyboardMatches(runes []rune) []match {
+	var matches []match
+	for _, row := range keyboardRows {
+		for i := 0; i+2 < len(runes); {
+			k := strings.IndexRune(row, unicode.ToLower(runes[i]))
+			if k == -1 || i+1 >= len(runes) {
+				i++
+				continue
+			}
+			next := strings.IndexRune(row, unicode.ToLower(runes[i+1]))
+			dir := next - k
+			if next == -1 || dir != 1 && dir != -1 {
+				i++
+				continue
+			}
+			j := i + 1
+			for j+1 < len(runes) {
+				n := strings.IndexRune(row, unicode.ToLower(runes[j+1]))
+				if n == -1 || n-strings.IndexRune(row, unicode.ToLower(runes[j])) != dir {
+					break
+				}
+				j++
+			}
+			if j-i >= 2 {
+				g := float64(len(row)) * float64(j-i+1) * 2
+				matches = append(matches, newMatch(i, j, g, "Straight rows of keys are easy to guess"))
+			}
+			i = j
+		}
+	}
+	return matches
+}
+
+// yearMatches finds recent years, from 1900 to 2099.
+func yearMatches(runes []rune) []match {
+	var matches []match
+	now := time.Now().Year()
+	for i := 0; i+4 <= len(runes); i++ {
+		year := 0
+		for _, r := range runes[i : i+4] {
+			if r < '0' || r > '9' {
+				year = -1
+				break
+			}
+			year = year*10 + int(r-'0')
+		}
+		if year < 1900 || year > 2099 {
+			continue
+		}
+		g := math.Max(math.Abs(float64(year-now)), 20)
+		matches = append(matches, newMatch(i, i+3, g, "Recent years are easy to guess"))
+	}
+	return matches
+}
@@ -0,0 +1,198 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package strength
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// PWQualityConf is the configuration file of libpwquality.
+const PWQualityConf = "/etc/security/pwquality.conf"
+
+// Defaults and limits of libpwquality.
+const (
+	minLenDefault = 8
+	minLenBase    = 6
+)
+
+// QualityError is returned by Checker.Check when a password is rejected.
+type QualityError struct {
+	Reason string
+}
+
+func (e *QualityError) Error() string {
+	return "strength: weak password: " + e.Reason
+}
+
+// Checker is a QualityChecker with the basic checks of libpwquality: the
+// length, with credits for the classes of characters, the number of classes
+// and the similarity with the user name. Instead of the dictionary check of
+// cracklib, it rejects the passwords whose Score is below MinScore.
+type Checker struct {
+	// MinLen is the minimum length of the password, less the credits; 8 if
+	// it is zero, and not lower than 6.
+	MinLen int
+
+	// DCredit, UCredit, LCredit and OCredit are the credits for digits,
+	// uppercase, lowercase and other characters. A positive credit is the
+	// maximum of characters of the class which count twice towards MinLen; a
+	// negative one is the minimum of characters of the class required.
+	DCredit, UCredit, LCredit, OCredit int
+
+	// MinClass is the minimum of classes of characters required.
+	MinClass int
+
+	// NoUserCheck disables the rejection of the passwords which contain the
+	// user name, straight or reversed, of 3 characters or more.
+	NoUserCheck bool
+
+	// MinScore is the lowest Score accepted, from 0 to 4, estimated with the
+	// user name as user input.
+	MinScore int
+}
+
+var _ QualityChecker = (*Checker)(nil)
+
+// Check returns a *QualityError if the password fails a check.
+func (c *Checker) Check(user, password string) error {
+	var digits, uppers, lowers, others int
+	for _, r := range password {
+		switch {
+		case unicode.IsDigit(r):
+			digits++
+		case unicode.IsUpper(r):
+			uppers++
+		case unicode.IsLower(r):
+			lowers++
+		default:
+			others++
+		}
+	}
+
+	minLen := c.MinLen
+	if minLen == 0 {
+		minLen = minLenDefault
+	} else if minLen < minLenBase {
+		minLen = minLenBase
+	}
+
+	// As libpwquality, each credit lowers the required length or requires
+	// a minimum of characters of its class.
+	size := minLen
+	for _, class := range []struct {
+		n, credit int
+		name      string
+	}{
+		{digits, c.DCredit, "digits"},
+		{uppers, c.UCredit, "uppercase letters"},
+		{lowers, c.LCredit, "lowercase letters"},
+		{others, c.OCredit, "other characters"},
+	} {
+		if class.credit >= 0 {
+			size -= min(class.n, class.credit)
+		} else if class.n < -class.credit {
+			return &QualityError{fmt.Sprintf("fewer than %d %s", -class.credit, class.name)}
+		}
+	}
+
+	classes := 0
+	for _, n := range []int{digits, uppers, lowers, others} {
+		if n != 0 {
+			classes++
+		}
+	}
+	if classes < c.MinClass {
+		return &QualityError{fmt.Sprintf("fewer than %d character classes", c.MinClass)}
+	}
+	if len([]rune(password)) < size {
+		return &QualityError{fmt.Sprintf("shorter than %d characters", size)}
+	}
+
+	if !c.NoUserCheck && len([]rune(user)) >= 3 {
+		lower, name := strings.ToLower(password), []rune(strings.ToLower(user))
+		reversed := make([]rune, len(name))
+		for i, r := range name {
+			reversed[len(name)-1-i] = r
+		}
+		if strings.Contains(lower, string(name)) || strings.Contains(lower, string(reversed)) {
+			return &QualityError{"contains the user name"}
+		}
+	}
+
+	var userInputs []string
+	if user != "" {
+		userInputs = []string{user}
+	}
+	if r := Score(password, userInputs...); r.Score < c.MinScore {
+		reason := "too guessable"
+		if r.Warning != "" {
+			reason += ": " + r.Warning
+		}
+		return &QualityError{reason}
+	}
+	return nil
+}
+
+// ReadPWQualityConf returns a Checker with the settings minlen, dcredit,
+// ucredit, lcredit, ocredit, minclass and usercheck of a file in the format of
+// pwquality.conf; the other settings are ignored. The minimum score is set to
+// minScore.
+func ReadPWQualityConf(name string, minScore int) (*Checker, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	c := &Checker{MinScore: minScore}
+	userCheck := 1
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := s.Text()
+		if i := strings.IndexByte(line, '#'); i != -1 {
+			line = line[:i]
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		var v *int
+		switch key {
+		case "minlen":
+			v = &c.MinLen
+		case "dcredit":
+			v = &c.DCredit
+		case "ucredit":
+			v = &c.UCredit
+		case "lcredit":
+			v = &c.LCredit
+		case "ocredit":
+			v = &c.OCredit
+		case "minclass":
+			v = &c.MinClass
+		case "usercheck":
+			v = &userCheck
+		default:
+			continue
+		}
+		if *v, err = strconv.Atoi(value); err != nil {
+			return nil, fmt.Errorf("strength: invalid %s: %s", key, value)
+		}
+	}
+	if err = s.Err(); err != nil {
+		return nil, err
+	}
+	c.NoUserCheck = userCheck == 0
+	return c, nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package strength
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestChecker(t *testing.T) {
+	data := []struct {
+		c        Checker
+		user     string
+		password string
+		ok       bool
+	}{
+		{Checker{}, "", "abcdefgh", true},
+		{Checker{}, "", "abcdefg", false},
+		{Checker{MinLen: 1}, "", "abcdef", true},
+		{Checker{MinLen: 1}, "", "abcde", false},
+		{Checker{MinLen: 10, DCredit: 1, UCredit: 1}, "", "Abcdefg1", true},
+		{Checker{MinLen: 10, DCredit: 1, UCredit: 1}, "", "Abcdefgh", false},
+		{Checker{MinLen: 10, DCredit: 2}, "", "abcdef12", true},
+		{Checker{MinLen: 10, DCredit: 2}, "", "abcde12", false},
+		{Checker{DCredit: -2}, "", "abcdefg1", false},
+		{Checker{DCredit: -2}, "", "abcdef12", true},
+		{Checker{UCredit: -1, OCredit: -1}, "", "Abcdefg!", true},
+		{Checker{UCredit: -1, OCredit: -1}, "", "abcdefg!", false},
+		{Checker{MinClass: 3}, "", "abcdefG1", true},
+		{Checker{MinClass: 3}, "", "abcdefgh1", false},
+		{Checker{}, "jsmith", "xjsmith99", false},
+		{Checker{}, "jsmith", "XJSMITH99", false},
+		{Checker{}, "jsmith", "htimsj99", false},
+		{Checker{NoUserCheck: true}, "jsmith", "xjsmith99", true},
+		{Checker{}, "js", "xjs12345", true},
+		{Checker{MinScore: 3}, "", "password1234", false},
+		{Checker{MinScore: 3}, "", "correcthorsebatterystaple", true},
+		{Checker{MinScore: 3, NoUserCheck: true}, "Tr0ub4dour", "Tr0ub4dour&3", false},
+	}
+
+	for i, d := range data {
+		err := d.c.Check(d.user, d.password)
+		if d.ok && err != nil {
+			t.Errorf("Test %d failed: %q: %v", i, d.password, err)
+		} else if !d.ok {
+			if _, ok := err.(*QualityError); !ok {
+				t.Errorf("Test %d failed: %q\nExpected a quality error, got: %v", i, d.password, err)
+			}
+		}
+	}
+}
+
+func TestReadPWQualityConf(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "pwquality.conf")
+	conf := `# Configuration for systemwide password quality limits
+# difok = 1
+minlen = 12
+dcredit = -1
+ucredit=1
+  lcredit = 0 # A comment.
+ocredit = -2
+minclass = 2
+usercheck = 0
+dictcheck = 1
+`
+	if err := os.WriteFile(name, []byte(conf), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := ReadPWQualityConf(name, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Checker{
+		MinLen: 12, DCredit: -1, UCredit: 1, OCredit: -2, MinClass: 2,
+		NoUserCheck: true, MinScore: 2,
+	}
+	if *c != want {
+		t.Errorf("Expected: %+v, got: %+v", want, *c)
+	}
+
+	if err = os.WriteFile(name, []byte("minlen = many\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ReadPWQualityConf(name, 0); err == nil {
+		t.Error("Expected an error")
+	}
+}
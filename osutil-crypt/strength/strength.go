@@ -0,0 +1,155 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+// Package strength estimates the strength of passwords, to reject weak ones
+// before hashing them.
+//
+// The estimation follows zxcvbn of Dropbox: the password is split into the
+// patterns an attacker would try first (common passwords, words given by the
+// user, sequences, repeats, keyboard rows and years) and brute force for the
+// rest, and its strength is the lowest number of guesses over every split.
+// The common passwords, English words, names and surnames are the frequency
+// lists of zxcvbn, ranked by frequency.
+//
+// The description of zxcvbn can be found here:
+// https://www.usenix.org/conference/usenixsecurity16/technical-sessions/presentation/wheeler
+package strength
+
+import (
+	"math"
+	"strings"
+)
+
+// maxLen is the number of characters which are examined at once; a longer
+// password is examined in chunks of this length, and a chunk which already
+// occurs earlier in the password only counts as a repeat.
+const maxLen = 100
+
+// Result is the estimated strength of a password.
+type Result struct {
+	// Score goes from 0, too guessable, to 4, very unguessable.
+	Score int
+
+	// Guesses is the estimated number of guesses to find the password.
+	Guesses float64
+
+	// Warning explains a low score, if there is a reason.
+	Warning string
+}
+
+// Score estimates the strength of the password. The user inputs are words an
+// attacker could know, i.e. the user name or the e-mail address, which should
+// not be part of the password.
+func Score(password string, userInputs ...string) Result {
+	runes := []rune(password)
+	if len(runes) == 0 {
+		return Result{Score: 0, Guesses: 1, Warning: "The password is empty"}
+	}
+
+	dict := newDictionary(userInputs)
+	guesses := 1.0
+	var m *match
+	n := 0
+	for start := 0; start < len(runes); start += maxLen {
+		chunk := runes[start:]
+		if len(chunk) > maxLen {
+			chunk = chunk[:maxLen]
+		}
+		n++
+		if start != 0 && strings.Contains(string(runes[:start+len(chunk)-1]), string(chunk)) {
+			continue
+		}
+
+		g, longest := mostGuessable(chunk, dict)
+		guesses *= g
+		if m == nil {
+			m = longest
+		}
+	}
+	// The number of chunks counts as the count of a repeat, and the guesses
+	// are kept finite.
+	guesses = math.Min(guesses*float64(n), math.MaxFloat64)
+
+	r := Result{Score: score(guesses), Guesses: guesses}
+	if r.Score <= 2 && m != nil {
+		r.Warning = m.warning
+	}
+	return r
+}
+
+// score maps the guesses to the score, as zxcvbn does.
+func score(guesses float64) int {
+	const delta = 5
+	switch {
+	case guesses < 1e3+delta:
+		return 0
+	case guesses < 1e6+delta:
+		return 1
+	case guesses < 1e8+delta:
+		return 2
+	case guesses < 1e10+delta:
+		return 3
+	}
+	return 4
+}
+
+// mostGuessable returns the lowest number of guesses of the password over
+// every split into matches and brute-forced characters, and the longest match
+// of that split.
+func mostGuessable(runes []rune, dict *dictionary) (float64, *match) {
+	matches := findMatches(runes, dict)
+
+	// best[i] is the lowest number of guesses of runes[:i], whose last part
+	// is last[i], or nil if it is a brute-forced character.
+	best := make([]float64, len(runes)+1)
+	last := make([]*match, len(runes)+1)
+	best[0] = 1
+	for i := 1; i <= len(runes); i++ {
+		best[i] = best[i-1] * cardinality(runes[i-1])
+		for k := range matches {
+			m := &matches[k]
+			if m.j+1 == i {
+				if g := best[m.i] * m.guesses; g < best[i] {
+					best[i], last[i] = g, m
+				}
+			}
+		}
+	}
+
+	var longest *match
+	for i := len(runes); i > 0; {
+		if m := last[i]; m != nil {
+			if longest == nil || m.j-m.i > longest.j-longest.i {
+				longest = m
+			}
+			i = m.i
+		} else {
+			i--
+		}
+	}
+
+	return math.Max(best[len(runes)], 1), longest
+}
+
+// cardinality returns the number of characters of the class of r, which is
+// what brute force tries for each character.
+func cardinality(r rune) float64 {
+	switch {
+	case '0' <= r && r <= '9':
+		return 10
+	case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z':
+		return 26
+	case r < 0x80:
+		return 33
+	}
+	return 100
+}
+
+// QualityChecker checks the quality of the new password of a user, returning
+// an error to reject it.
+type QualityChecker interface {
+	Check(user, password string) error
+}
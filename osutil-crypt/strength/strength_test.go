@@ -0,0 +1,101 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package strength
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestScore(t *testing.T) {
+	data := []struct {
+		password   string
+		userInputs []string
+		score      int
+	}{
+		{"", nil, 0},
+		{"password", nil, 0},
+		{"Password", nil, 0},
+		{"p4ssw0rd", nil, 0},
+		{"drowssap", nil, 0},
+		{"123456789", nil, 0},
+		{"qwertyuiop", nil, 0},
+		{"aaaaaaaaaaaa", nil, 0},
+		{"abcdefgh", nil, 0},
+		{"abcabcabcabc", nil, 0},
+		{"1987", nil, 0},
+		{"jsmith1987", []string{"jsmith@example.com"}, 1},
+		{"jsmith1987", nil, 1},
+		{"Qwerty1987!", nil, 1},
+		{"chocolate", nil, 1},
+		{"butterfly", nil, 1},
+		{"elephant", nil, 0},
+		{"liverpool", nil, 0},
+		{"basketball", nil, 1},
+		{"Liverpool1!", nil, 1},
+		{"pokemon", nil, 0},
+		{"jennifer", nil, 0},
+		{"Tr0ub4dour&3", nil, 2},
+		{"correcthorsebatterystaple", nil, 4},
+		{"8hK#w2!qZr@9Lp", nil, 4},
+	}
+
+	for i, d := range data {
+		r := Score(d.password, d.userInputs...)
+		if r.Score != d.score {
+			t.Errorf("Test %d failed: %q\nExpected: %d, got: %d (%g guesses)",
+				i, d.password, d.score, r.Score, r.Guesses)
+		}
+		if r.Score <= 2 && r.Warning == "" {
+			t.Errorf("Test %d failed: %q has no warning", i, d.password)
+		}
+	}
+}
+
+func TestScoreWarning(t *testing.T) {
+	data := []struct {
+		password   string
+		userInputs []string
+		warning    string
+	}{
+		{"password", nil, "This is a top-20 common password"},
+		{"abcdefgh", nil, "Sequences like abc or 6543 are easy to guess"},
+		{"asdfghjkl", nil, "Straight rows of keys are easy to guess"},
+		{"jsmith", []string{"John Smith", "jsmith"}, "Personal information is easy to guess"},
+		{"chocolate", nil, "A word by itself is easy to guess"},
+		{"Smith", nil, "Names and surnames are easy to guess"},
+	}
+
+	for i, d := range data {
+		if r := Score(d.password, d.userInputs...); r.Warning != d.warning {
+			t.Errorf("Test %d failed: %q\nExpected: %s, got: %s", i, d.password, d.warning, r.Warning)
+		}
+	}
+}
+
+func TestScoreLong(t *testing.T) {
+	for i, n := range []int{100, 200, 250, 1000} {
+		password := strings.Repeat("a", n)
+		if r := Score(password); r.Score > 1 {
+			t.Errorf("Test %d failed: %d characters\nExpected: 1 at most, got: %d (%g guesses)",
+				i, n, r.Score, r.Guesses)
+		}
+	}
+
+	// A random password of 400 characters would overflow the guesses.
+	rnd := rand.New(rand.NewSource(1))
+	runes := make([]rune, 400)
+	for i := range runes {
+		runes[i] = rune('!' + rnd.Intn(94))
+	}
+	r := Score(string(runes))
+	if math.IsInf(r.Guesses, 0) || r.Score != 4 {
+		t.Errorf("Expected finite guesses with a score of 4, got: %+v", r)
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package crypt
+
+import (
+	"bufio"
+	"errors"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+
+	"trident.li/go/osutil-crypt/bcrypt_crypt"
+	"trident.li/go/osutil-crypt/common"
+	"trident.li/go/osutil-crypt/des_crypt"
+	"trident.li/go/osutil-crypt/sha512_crypt"
+)
+
+// LoginDefs is the configuration file of the shadow password suite.
+const LoginDefs = "/etc/login.defs"
+
+// Defaults and limits of shadow and libxcrypt.
+const (
+	bcryptRoundsDefault   = 13
+	yescryptCostDefault   = 5
+	yescryptCostMax       = 10 // 11 in libxcrypt, which exceeds yescrypt_crypt.WorkMax.
+	yescryptBlockSizeLow  = 8  // r for the costs 1 and 2.
+	yescryptBlockSizeHigh = 32
+)
+
+// SystemDefault returns the crypter which passwd(1) would use on this host,
+// configured from ENCRYPT_METHOD, SHA_CRYPT_MIN_ROUNDS, SHA_CRYPT_MAX_ROUNDS,
+// BCRYPT_MIN_ROUNDS, BCRYPT_MAX_ROUNDS and YESCRYPT_COST_FACTOR of LoginDefs.
+//
+// As shadow does, the rounds are chosen at random between the minimum and the
+// maximum. The DES-based crypt is refused with des_crypt.ErrGenerate, and the
+// crypt functions not allowed by SetAllowedAlgorithms with a
+// *crypt.PolicyError; as for New, the crypter returns it too from Generate and
+// Verify if its crypt function is disallowed later.
+func SystemDefault() (crypt.Crypter, error) {
+	return systemDefault(LoginDefs)
+}
+
+func systemDefault(name string) (crypt.Crypter, error) {
+	defs, err := readLoginDefs(name)
+	if err != nil {
+		return nil, err
+	}
+
+	method := strings.ToUpper(defs["ENCRYPT_METHOD"])
+	if method == "" {
+		method = "DES"
+		if strings.EqualFold(defs["MD5_CRYPT_ENAB"], "yes") {
+			method = "MD5"
+		}
+	}
+
+	var p crypt.Params
+	var id crypt.Crypt
+
+	switch method {
+	case "DES":
+		return nil, des_crypt.ErrGenerate
+	case "MD5":
+		id = crypt.MD5
+	case "SHA256", "SHA512":
+		id = crypt.SHA256
+		if method == "SHA512" {
+			id = crypt.SHA512
+		}
+		p.Rounds, err = rounds(defs, "SHA_CRYPT", 0)
+		if err != nil {
+			return nil, err
+		}
+		// shadow keeps the rounds in the range of SHA-crypt.
+		if p.Rounds != 0 && p.Rounds < sha512_crypt.RoundsMin {
+			p.Rounds = sha512_crypt.RoundsMin
+		} else if p.Rounds > sha512_crypt.RoundsMax {
+			p.Rounds = sha512_crypt.RoundsMax
+		}
+	case "BCRYPT":
+		id = crypt.BCRYPT
+		p.Rounds, err = rounds(defs, "BCRYPT", bcryptRoundsDefault)
+		if err != nil {
+			return nil, err
+		}
+		// shadow keeps the rounds in the range of bcrypt.
+		if p.Rounds < bcrypt_crypt.RoundsMin {
+			p.Rounds = bcrypt_crypt.RoundsMin
+		} else if p.Rounds > bcrypt_crypt.RoundsMax {
+			p.Rounds = bcrypt_crypt.RoundsMax
+		}
+	case "YESCRYPT":
+		id = crypt.YESCRYPT
+		cost := yescryptCostDefault
+		if v, ok := defs["YESCRYPT_COST_FACTOR"]; ok {
+			if cost, err = strconv.Atoi(v); err != nil {
+				return nil, errors.New("crypt: invalid YESCRYPT_COST_FACTOR: " + v)
+			}
+		}
+		if cost < 1 {
+			cost = 1
+		} else if cost > yescryptCostMax {
+			cost = yescryptCostMax
+		}
+		// The same N and r as the salts of libxcrypt for each cost.
+		if cost <= 2 {
+			p = crypt.Params{Rounds: cost + 9, BlockSize: yescryptBlockSizeLow}
+		} else {
+			p = crypt.Params{Rounds: cost + 7, BlockSize: yescryptBlockSizeHigh}
+		}
+	default:
+		return nil, errors.New("crypt: unsupported ENCRYPT_METHOD: " + method)
+	}
+
+	if !crypt.Allowed(id) {
+		return nil, &crypt.PolicyError{Crypt: id}
+	}
+	// From the registry, so that SetAllowedAlgorithms applies to it later too.
+	c := crypt.New(id)
+	if err = c.SetParams(p); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// rounds returns a random number of rounds between <prefix>_MIN_ROUNDS and
+// <prefix>_MAX_ROUNDS, or the one which is set, or else def.
+func rounds(defs map[string]string, prefix string, def int) (int, error) {
+	min, max := -1, -1
+	for _, r := range []struct {
+		name string
+		v    *int
+	}{{prefix + "_MIN_ROUNDS", &min}, {prefix + "_MAX_ROUNDS", &max}} {
+		s, ok := defs[r.name]
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			return 0, errors.New("crypt: invalid " + r.name + ": " + s)
+		}
+		*r.v = n
+	}
+
+	switch {
+	case min == -1 && max == -1:
+		return def, nil
+	case min == -1:
+		return max, nil
+	case max == -1 || max <= min:
+		return min, nil
+	}
+	return min + rand.Intn(max-min+1), nil
+}
+
+// readLoginDefs returns the settings of a file in the format of login.defs:
+// a name and a value per line, with comments from '#'.
+func readLoginDefs(name string) (map[string]string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	defs := make(map[string]string)
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := s.Text()
+		if i := strings.IndexByte(line, '#'); i != -1 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		defs[fields[0]] = strings.Trim(fields[1], `"`)
+	}
+	return defs, s.Err()
+}
@@ -0,0 +1,171 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package crypt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"trident.li/go/osutil-crypt/common"
+	"trident.li/go/osutil-crypt/des_crypt"
+)
+
+func TestSystemDefault(t *testing.T) {
+	data := []struct {
+		defs   string
+		prefix string
+		params crypt.Params
+	}{
+		{"MD5_CRYPT_ENAB yes\n", "$1$", crypt.Params{Rounds: 1000}},
+		{"ENCRYPT_METHOD MD5\n", "$1$", crypt.Params{Rounds: 1000}},
+		{"ENCRYPT_METHOD SHA256\n", "$5$", crypt.Params{Rounds: 5000}},
+		{
+			"# A comment.\nENCRYPT_METHOD\tSHA512 # Another one.\nSHA_CRYPT_MIN_ROUNDS 6000\n",
+			"$6$rounds=6000$",
+			crypt.Params{Rounds: 6000},
+		},
+		{
+			"ENCRYPT_METHOD SHA512\nSHA_CRYPT_MIN_ROUNDS 7000\nSHA_CRYPT_MAX_ROUNDS 7000\n",
+			"$6$rounds=7000$",
+			crypt.Params{Rounds: 7000},
+		},
+		{
+			"ENCRYPT_METHOD SHA512\nSHA_CRYPT_MAX_ROUNDS 10\n",
+			"$6$rounds=1000$",
+			crypt.Params{Rounds: 1000},
+		},
+		{
+			"ENCRYPT_METHOD BCRYPT\nBCRYPT_MIN_ROUNDS 5\n",
+			"$2b$05$",
+			crypt.Params{Rounds: 5},
+		},
+		{
+			"ENCRYPT_METHOD BCRYPT\nBCRYPT_MIN_ROUNDS 3\nBCRYPT_MAX_ROUNDS 3\n",
+			"$2b$04$",
+			crypt.Params{Rounds: 4},
+		},
+		{"ENCRYPT_METHOD YESCRYPT\n", "$y$j9T$", crypt.Params{Rounds: 12, BlockSize: 32, Parallelism: 1}},
+		{
+			"ENCRYPT_METHOD YESCRYPT\nYESCRYPT_COST_FACTOR 1\n",
+			"$y$j75$",
+			crypt.Params{Rounds: 10, BlockSize: 8, Parallelism: 1},
+		},
+	}
+
+	dir := t.TempDir()
+	name := filepath.Join(dir, "login.defs")
+
+	for i, d := range data {
+		if err := os.WriteFile(name, []byte(d.defs), 0644); err != nil {
+			t.Fatal(err)
+		}
+		c, err := systemDefault(name)
+		if err != nil {
+			t.Fatalf("Test %d failed: %v", i, err)
+		}
+
+		hash, err := c.Generate([]byte("password"), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(hash, d.prefix) {
+			t.Errorf("Test %d failed\nExpected prefix: %s, got: %s", i, d.prefix, hash)
+		}
+		p, err := c.Params(hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p != d.params {
+			t.Errorf("Test %d failed\nExpected: %+v, got: %+v", i, d.params, p)
+		}
+	}
+
+	// The highest cost of libxcrypt is above the limits of yescrypt_crypt.
+	if err := os.WriteFile(name, []byte("ENCRYPT_METHOD YESCRYPT\nYESCRYPT_COST_FACTOR 11\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := systemDefault(name); err != nil {
+		t.Error(err)
+	}
+
+	for i, d := range []string{
+		"ENCRYPT_METHOD DES\n",
+		"",
+		"ENCRYPT_METHOD NONE\n",
+		"ENCRYPT_METHOD SHA512\nSHA_CRYPT_MIN_ROUNDS many\n",
+	} {
+		if err := os.WriteFile(name, []byte(d), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := systemDefault(name); err == nil {
+			t.Errorf("Test %d failed: expected an error", i)
+		} else if i < 2 && err != des_crypt.ErrGenerate {
+			t.Errorf("Test %d failed\nExpected: %v, got: %v", i, des_crypt.ErrGenerate, err)
+		}
+	}
+}
+
+func TestSystemDefaultAllowed(t *testing.T) {
+	SetAllowedAlgorithms(crypt.SHA512, crypt.BCRYPT)
+	defer SetAllowedAlgorithms()
+
+	name := filepath.Join(t.TempDir(), "login.defs")
+	for i, d := range []struct {
+		defs  string
+		crypt crypt.Crypt
+	}{
+		{"ENCRYPT_METHOD MD5\n", crypt.MD5},
+		{"ENCRYPT_METHOD SHA256\n", crypt.SHA256},
+		{"ENCRYPT_METHOD SHA512\n", 0},
+	} {
+		if err := os.WriteFile(name, []byte(d.defs), 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := systemDefault(name)
+		if d.crypt == 0 {
+			if err != nil {
+				t.Errorf("Test %d failed: %v", i, err)
+			}
+		} else if e, ok := err.(*crypt.PolicyError); !ok || e.Crypt != d.crypt {
+			t.Errorf("Test %d failed\nExpected a policy error for %v, got: %v", i, d.crypt, err)
+		}
+	}
+}
+
+// TestSystemDefaultDisallowed checks that the crypter of SystemDefault is
+// refused once its crypt function is not allowed anymore.
+func TestSystemDefaultDisallowed(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "login.defs")
+	if err := os.WriteFile(name, []byte("ENCRYPT_METHOD SHA512\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := systemDefault(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := c.Generate([]byte("password"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	SetAllowedAlgorithms(crypt.BCRYPT)
+	defer SetAllowedAlgorithms()
+
+	if _, err = c.Generate([]byte("password"), nil); !isPolicyError(err, crypt.SHA512) {
+		t.Errorf("Expected a policy error for %v from Generate, got: %v", crypt.SHA512, err)
+	}
+	if err = c.Verify(hash, []byte("password")); !isPolicyError(err, crypt.SHA512) {
+		t.Errorf("Expected a policy error for %v from Verify, got: %v", crypt.SHA512, err)
+	}
+}
+
+func isPolicyError(err error, c crypt.Crypt) bool {
+	e, ok := err.(*crypt.PolicyError)
+	return ok && e.Crypt == c
+}
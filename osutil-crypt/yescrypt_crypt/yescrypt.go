@@ -0,0 +1,471 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package yescrypt_crypt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// This is a port of the reference implementation (yescrypt-ref.c) by Solar
+// Designer, restricted to what crypt(3) needs: no ROM and no hash upgrades.
+//
+// As the reference, the blocks are kept in the "SIMD shuffled" order, since
+// pwxform works on that order and its output depends on it.
+
+// Flags of the yescrypt modes.
+const (
+	flagWORM          = 0x001
+	flagRW            = 0x002
+	flagModeMask      = 0x003
+	flagRWFlavorMask  = 0x3fc
+	flagRWFlavorShift = 2
+	flagPrehash       = 0x10000000 // Internal.
+
+	// flagRWDefaults is RW with 6 rounds, gather 4, simple 2 and 12 KiB
+	// S-boxes; the only RW flavor supported.
+	flagRWDefaults = flagRW | 0x004 | 0x010 | 0x020 | 0x080
+)
+
+// pwxform settings.
+const (
+	pwxSimple = 2
+	pwxGather = 4
+	pwxRounds = 6
+	sWidth    = 8
+
+	pwxBytes = pwxGather * pwxSimple * 8
+	pwxWords = pwxBytes / 4
+	sBytes   = 3 * (1 << sWidth) * pwxSimple * 8
+	sWords   = sBytes / 4
+	sMask    = ((1 << sWidth) - 1) * pwxSimple * 8
+)
+
+var errParams = errors.New("yescrypt: invalid parameters")
+
+type pwxformCtx struct {
+	s          []uint32
+	s0, s1, s2 []uint32
+	w          int
+}
+
+// yescrypt computes the yescrypt KDF of passwd and salt into a key of keyLen
+// bytes.
+func yescrypt(passwd, salt []byte, flags int, N uint64, r, p, t uint32,
+	keyLen int) ([]byte, error) {
+
+	if flags&(flagRW|flagPrehash) == flagRW && p >= 1 &&
+		N/uint64(p) >= 0x100 && N/uint64(p)*uint64(r) >= 0x20000 {
+		dk, err := yescryptBody(passwd, salt, flags|flagPrehash, N>>6, r, p, 0, 32)
+		if err != nil {
+			return nil, err
+		}
+		passwd = dk
+	}
+
+	return yescryptBody(passwd, salt, flags, N, r, p, t, keyLen)
+}
+
+func yescryptBody(passwd, salt []byte, flags int, N uint64, r, p, t uint32,
+	keyLen int) ([]byte, error) {
+
+	switch flags & flagModeMask {
+	case 0:
+		if flags != 0 || t != 0 {
+			return nil, errParams
+		}
+	case flagWORM:
+		if flags != flagWORM {
+			return nil, errParams
+		}
+	case flagRW:
+		if flags&^flagPrehash != flagRWDefaults {
+			return nil, errParams
+		}
+	default:
+		return nil, errParams
+	}
+
+	if r < 1 || p < 1 || uint64(r)*uint64(p) >= 1<<30 ||
+		N < 2 || N&(N-1) != 0 || N/uint64(p) <= 1 ||
+		N+uint64(p) > MemoryMax/128/uint64(r) {
+		return nil, errParams
+	}
+
+	s := 32 * int(r)
+	V := make([]uint32, uint64(s)*N)
+	XY := make([]uint32, 2*s)
+
+	var ctx []pwxformCtx
+	if flags&flagRW != 0 {
+		ctx = make([]pwxformCtx, p)
+		for i := range ctx {
+			ctx[i].s = make([]uint32, sWords)
+		}
+	}
+
+	var sum [sha256.Size]byte
+	if flags != 0 {
+		key := []byte("yescrypt-prehash")
+		if flags&flagPrehash == 0 {
+			key = key[:8]
+		}
+		h := hmac.New(sha256.New, key)
+		h.Write(passwd)
+		h.Sum(sum[:0])
+		passwd = sum[:]
+	}
+
+	b := pbkdf2.Key(passwd, salt, 1, 128*int(r)*int(p), sha256.New)
+	if flags != 0 {
+		copy(sum[:], b)
+	}
+	B := make([]uint32, len(b)/4)
+	for i := range B {
+		B[i] = binary.LittleEndian.Uint32(b[i*4:])
+	}
+
+	if flags&flagRW != 0 {
+		smix(B, s, N, p, t, flags, V, XY, ctx, passwd)
+	} else {
+		for i := 0; i < int(p); i++ {
+			smix(B[s*i:], s, N, 1, t, flags, V, XY, nil, nil)
+		}
+	}
+
+	for i := range B {
+		binary.LittleEndian.PutUint32(b[i*4:], B[i])
+	}
+	dk := pbkdf2.Key(passwd, b, 1, keyLen, sha256.New)
+
+	if flags != 0 && flags&flagPrehash == 0 {
+		// Compute ClientKey and then StoredKey, as SCRAM does.
+		h := hmac.New(sha256.New, dk[:32])
+		h.Write([]byte("Client Key"))
+		clientKey := sha256.Sum256(h.Sum(nil))
+		copy(dk, clientKey[:])
+	}
+
+	// Clean sensitive data.
+	for i := range b {
+		b[i] = 0
+	}
+	for i := range V {
+		V[i] = 0
+	}
+
+	return dk, nil
+}
+
+// smix computes B = SMix_r(B, N) for the p blocks of B; s is 32r.
+func smix(B []uint32, s int, N uint64, p, t uint32, flags int,
+	V, XY []uint32, ctx []pwxformCtx, passwd []byte) {
+
+	r := s / 32
+	nChunk := N / uint64(p)
+	nLoopAll := nChunk
+	if flags&flagRW != 0 {
+		if t <= 1 {
+			if t != 0 {
+				nLoopAll *= 2 // 2/3
+			}
+			nLoopAll = (nLoopAll + 2) / 3 // 1/3, round up
+		} else {
+			nLoopAll *= uint64(t - 1)
+		}
+	} else if t != 0 {
+		if t == 1 {
+			nLoopAll += (nLoopAll + 1) / 2 // 1.5, round up
+		}
+		nLoopAll *= uint64(t)
+	}
+
+	var nLoopRW uint64
+	if flags&flagRW != 0 {
+		nLoopRW = nLoopAll / uint64(p)
+	}
+
+	nChunk &^= 1                   // round down to even
+	nLoopAll = (nLoopAll + 1) &^ 1 // round up to even
+	nLoopRW = (nLoopRW + 1) &^ 1
+
+	var vChunk uint64
+	for i := 0; i < int(p); i++ {
+		np := nChunk
+		if i == int(p)-1 {
+			np = N - vChunk
+		}
+		Bp := B[s*i : s*(i+1)]
+		Vp := V[uint64(s)*vChunk:]
+
+		var c *pwxformCtx
+		if flags&flagRW != 0 {
+			c = &ctx[i]
+			// The S-boxes are filled by SMix1 with r = 1 and no flags.
+			smix1(Bp, 1, sBytes/128, 0, c.s, XY, nil)
+			c.s2 = c.s[:sWords/3]
+			c.s1 = c.s[sWords/3 : 2*sWords/3]
+			c.s0 = c.s[2*sWords/3:]
+			c.w = 0
+
+			if i == 0 {
+				key := make([]byte, 64)
+				for k := range key[:16] {
+					binary.LittleEndian.PutUint32(key[k*4:], Bp[s-16+k])
+				}
+				h := hmac.New(sha256.New, key)
+				h.Write(passwd)
+				h.Sum(passwd[:0])
+			}
+		}
+
+		smix1(Bp, r, np, flags, Vp, XY, c)
+		smix2(Bp, r, p2floor(np), nLoopRW, flags, Vp, XY, c)
+
+		vChunk += nChunk
+	}
+
+	for i := 0; i < int(p); i++ {
+		var c *pwxformCtx
+		if flags&flagRW != 0 {
+			c = &ctx[i]
+		}
+		smix2(B[s*i:s*(i+1)], r, N, nLoopAll-nLoopRW, flags&^flagRW, V, XY, c)
+	}
+}
+
+// smix1 computes the first loop of SMix_r over N blocks of V.
+func smix1(B []uint32, r int, N uint64, flags int, V, XY []uint32, ctx *pwxformCtx) {
+	s := 32 * r
+	X := XY[:s]
+	Y := XY[s : 2*s]
+
+	for k := 0; k < 2*r; k++ {
+		for i := 0; i < 16; i++ {
+			X[k*16+i] = B[k*16+(i*5%16)]
+		}
+	}
+
+	for i := uint64(0); i < N; i++ {
+		copy(V[i*uint64(s):], X)
+		if flags&flagRW != 0 && i > 1 {
+			j := wrap(integerify(X, r), i)
+			blkxor(X, V[j*uint64(s):])
+		}
+
+		if ctx != nil {
+			blockmixPwxform(X, ctx, r)
+		} else {
+			blockmixSalsa8(X, Y, r)
+		}
+	}
+
+	for k := 0; k < 2*r; k++ {
+		for i := 0; i < 16; i++ {
+			B[k*16+(i*5%16)] = X[k*16+i]
+		}
+	}
+}
+
+// smix2 computes the second loop of SMix_r, with Nloop iterations.
+func smix2(B []uint32, r int, N, nLoop uint64, flags int, V, XY []uint32, ctx *pwxformCtx) {
+	if nLoop == 0 {
+		return
+	}
+
+	s := 32 * r
+	X := XY[:s]
+	Y := XY[s : 2*s]
+
+	for k := 0; k < 2*r; k++ {
+		for i := 0; i < 16; i++ {
+			X[k*16+i] = B[k*16+(i*5%16)]
+		}
+	}
+
+	for i := uint64(0); i < nLoop; i++ {
+		j := integerify(X, r) & (N - 1)
+		blkxor(X, V[j*uint64(s):])
+		if flags&flagRW != 0 {
+			copy(V[j*uint64(s):], X)
+		}
+
+		if ctx != nil {
+			blockmixPwxform(X, ctx, r)
+		} else {
+			blockmixSalsa8(X, Y, r)
+		}
+	}
+
+	for k := 0; k < 2*r; k++ {
+		for i := 0; i < 16; i++ {
+			B[k*16+(i*5%16)] = X[k*16+i]
+		}
+	}
+}
+
+func blockmixSalsa8(B, Y []uint32, r int) {
+	var X [16]uint32
+
+	copy(X[:], B[(2*r-1)*16:])
+	for i := 0; i < 2*r; i++ {
+		blkxor(X[:], B[i*16:])
+		salsa20(X[:], 8)
+		copy(Y[i*16:], X[:])
+	}
+
+	for i := 0; i < r; i++ {
+		copy(B[i*16:(i+1)*16], Y[(i*2)*16:])
+	}
+	for i := 0; i < r; i++ {
+		copy(B[(i+r)*16:(i+r+1)*16], Y[(i*2+1)*16:])
+	}
+}
+
+func blockmixPwxform(B []uint32, ctx *pwxformCtx, r int) {
+	var X [pwxWords]uint32
+
+	r1 := 128 * r / pwxBytes
+	copy(X[:], B[(r1-1)*pwxWords:])
+	for i := 0; i < r1; i++ {
+		if r1 > 1 {
+			blkxor(X[:], B[i*pwxWords:])
+		}
+		pwxform(X[:], ctx)
+		copy(B[i*pwxWords:], X[:])
+	}
+
+	i := (r1 - 1) * pwxBytes / 64
+	salsa20(B[i*16:(i+1)*16], 2)
+	for i++; i < 2*r; i++ {
+		blkxor(B[i*16:(i+1)*16], B[(i-1)*16:])
+		salsa20(B[i*16:(i+1)*16], 2)
+	}
+}
+
+func pwxform(B []uint32, ctx *pwxformCtx) {
+	S0, S1, S2 := ctx.s0, ctx.s1, ctx.s2
+	w := ctx.w
+
+	for i := 0; i < pwxRounds; i++ {
+		for j := 0; j < pwxGather; j++ {
+			X := B[j*pwxSimple*2 : (j+1)*pwxSimple*2]
+			p0 := S0[(X[0]&sMask)/4:]
+			p1 := S1[(X[1]&sMask)/4:]
+
+			for k := 0; k < pwxSimple; k++ {
+				s0 := uint64(p0[2*k+1])<<32 | uint64(p0[2*k])
+				s1 := uint64(p1[2*k+1])<<32 | uint64(p1[2*k])
+
+				x := uint64(X[2*k+1]) * uint64(X[2*k])
+				x += s0
+				x ^= s1
+
+				X[2*k] = uint32(x)
+				X[2*k+1] = uint32(x >> 32)
+
+				if i != 0 && i != pwxRounds-1 {
+					S2[2*w] = uint32(x)
+					S2[2*w+1] = uint32(x >> 32)
+					w++
+				}
+			}
+		}
+	}
+
+	ctx.s0, ctx.s1, ctx.s2 = S2, S0, S1
+	ctx.w = w & ((1<<sWidth)*pwxSimple - 1)
+}
+
+// salsa20 applies the Salsa20 core with the given rounds to a shuffled block.
+func salsa20(B []uint32, rounds int) {
+	var x [16]uint32
+
+	for i := 0; i < 16; i++ {
+		x[i*5%16] = B[i]
+	}
+
+	for i := 0; i < rounds; i += 2 {
+		// Operate on columns.
+		x[4] ^= rotl(x[0]+x[12], 7)
+		x[8] ^= rotl(x[4]+x[0], 9)
+		x[12] ^= rotl(x[8]+x[4], 13)
+		x[0] ^= rotl(x[12]+x[8], 18)
+
+		x[9] ^= rotl(x[5]+x[1], 7)
+		x[13] ^= rotl(x[9]+x[5], 9)
+		x[1] ^= rotl(x[13]+x[9], 13)
+		x[5] ^= rotl(x[1]+x[13], 18)
+
+		x[14] ^= rotl(x[10]+x[6], 7)
+		x[2] ^= rotl(x[14]+x[10], 9)
+		x[6] ^= rotl(x[2]+x[14], 13)
+		x[10] ^= rotl(x[6]+x[2], 18)
+
+		x[3] ^= rotl(x[15]+x[11], 7)
+		x[7] ^= rotl(x[3]+x[15], 9)
+		x[11] ^= rotl(x[7]+x[3], 13)
+		x[15] ^= rotl(x[11]+x[7], 18)
+
+		// Operate on rows.
+		x[1] ^= rotl(x[0]+x[3], 7)
+		x[2] ^= rotl(x[1]+x[0], 9)
+		x[3] ^= rotl(x[2]+x[1], 13)
+		x[0] ^= rotl(x[3]+x[2], 18)
+
+		x[6] ^= rotl(x[5]+x[4], 7)
+		x[7] ^= rotl(x[6]+x[5], 9)
+		x[4] ^= rotl(x[7]+x[6], 13)
+		x[5] ^= rotl(x[4]+x[7], 18)
+
+		x[11] ^= rotl(x[10]+x[9], 7)
+		x[8] ^= rotl(x[11]+x[10], 9)
+		x[9] ^= rotl(x[8]+x[11], 13)
+		x[10] ^= rotl(x[9]+x[8], 18)
+
+		x[12] ^= rotl(x[15]+x[14], 7)
+		x[13] ^= rotl(x[12]+x[15], 9)
+		x[14] ^= rotl(x[13]+x[12], 13)
+		x[15] ^= rotl(x[14]+x[13], 18)
+	}
+
+	for i := 0; i < 16; i++ {
+		B[i] += x[i*5%16]
+	}
+}
+
+func rotl(a uint32, b uint) uint32 { return a<<b | a>>(32-b) }
+
+func blkxor(dst, src []uint32) {
+	for i := range dst {
+		dst[i] ^= src[i]
+	}
+}
+
+// integerify returns the first 64 bits of the last 64-byte block of X, whose
+// second word is at index 13 due to the shuffling.
+func integerify(X []uint32, r int) uint64 {
+	x := X[(2*r-1)*16:]
+	return uint64(x[13])<<32 | uint64(x[0])
+}
+
+// p2floor returns the largest power of 2 not greater than x.
+func p2floor(x uint64) uint64 {
+	for y := x & (x - 1); y != 0; y = x & (x - 1) {
+		x = y
+	}
+	return x
+}
+
+func wrap(x, i uint64) uint64 {
+	n := p2floor(i)
+	return (x & (n - 1)) + (i - n)
+}
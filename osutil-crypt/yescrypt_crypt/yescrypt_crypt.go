@@ -0,0 +1,425 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+// Package yescrypt_crypt implements Solar Designer's yescrypt password hashing
+// algorithm, the default of /etc/shadow in several current distributions.
+//
+// The specification for this algorithm can be found here:
+// https://www.openwall.com/yescrypt/
+//
+// As explained in the package common, the parameters of a hashed key are
+// bounded: the memory, 128*r*(N+p) bytes, by MemoryMax, and the blocks mixed,
+// 128*N*r bytes by the time t (counted as 1 up to t = 1) and, without the RW
+// flavor, by p, by WorkMax, about 4 seconds of a CPU. So N = 2^17 with r = 32,
+// the cost 10 of libxcrypt, is the most a hashed key with t = 0 can ask.
+package yescrypt_crypt
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+
+	"trident.li/go/osutil-crypt/common"
+)
+
+const (
+	MagicPrefix   = "$y$"
+	SaltLenMin    = 1 // In bytes, before encoding.
+	SaltLenMax    = 64
+	RoundsMin     = 1 // The cost N, as a base-2 logarithm.
+	RoundsMax     = 63
+	RoundsDefault = 12
+
+	BlockSizeDefault = 32 // r
+
+	MemoryMax = 2 << 30   // In bytes.
+	WorkMax   = 512 << 20 // In bytes, by the passes.
+
+	KeyLen = 32
+)
+
+const alphabet = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// ErrUnsupported is returned for hashes using a ROM or hash upgrades, which
+// are not implemented.
+var ErrUnsupported = errors.New("yescrypt: unsupported parameters")
+
+// params are the parameters encoded in a "$y$" setting.
+type params struct {
+	flags   int
+	nLog2   uint32
+	r, p, t uint32
+}
+
+type crypter struct {
+	Salt crypt.Salt
+
+	r, p uint32
+}
+
+// New returns a new crypt.Crypter computing the yescrypt password hashing.
+func New() crypt.Crypter {
+	return &crypter{
+		Salt: crypt.Salt{
+			MagicPrefix:   []byte(MagicPrefix),
+			SaltLenMin:    SaltLenMin,
+			SaltLenMax:    SaltLenMax,
+			RoundsDefault: RoundsDefault,
+			RoundsMin:     RoundsMin,
+			RoundsMax:     RoundsMax,
+		},
+		r: BlockSizeDefault,
+		p: 1,
+	}
+}
+
+// Generate hashes the key with a salt of the form "$y$<params>$<salt>", where
+// the parameters and the salt are encoded as crypt(3) of libxcrypt does.
+func (c *crypter) Generate(key, salt []byte) (string, error) {
+	if len(salt) == 0 {
+		salt = c.generateSalt()
+	}
+	if !bytes.HasPrefix(salt, c.Salt.MagicPrefix) {
+		return "", crypt.ErrSaltPrefix
+	}
+
+	setting := salt[len(c.Salt.MagicPrefix):]
+	i := bytes.IndexByte(setting, '$')
+	if i == -1 {
+		return "", crypt.ErrSaltFormat
+	}
+	encParams := setting[:i]
+	p, err := decodeParams(encParams)
+	if err != nil {
+		return "", err
+	}
+
+	salt = setting[i+1:]
+	if i = bytes.IndexByte(salt, '$'); i != -1 {
+		salt = salt[:i]
+	}
+	rawSalt, err := decode64(salt)
+	if err != nil || len(rawSalt) < SaltLenMin || len(rawSalt) > SaltLenMax {
+		return "", crypt.ErrSaltFormat
+	}
+
+	sum, err := yescrypt(key, rawSalt, p.flags, 1<<p.nLog2, p.r, p.p, p.t, KeyLen)
+	if err != nil {
+		return "", crypt.ErrSaltRounds
+	}
+
+	out := make([]byte, 0, len(c.Salt.MagicPrefix)+len(encParams)+len(salt)+45)
+	out = append(out, c.Salt.MagicPrefix...)
+	out = append(out, encParams...)
+	out = append(out, '$')
+	out = append(out, salt...)
+	out = append(out, '$')
+	out = append(out, crypt.Base64_24Bit(sum)...)
+
+	// Clean sensitive data.
+	for i := range sum {
+		sum[i] = 0
+	}
+
+	return string(out), nil
+}
+
+func (c *crypter) Verify(hashedKey string, key []byte) error {
+	newHash, err := c.Generate(key, []byte(hashedKey))
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare([]byte(newHash), []byte(hashedKey)) != 1 {
+		return crypt.ErrKeyMismatch
+	}
+	return nil
+}
+
+// Cost returns the base-2 logarithm of the cost N used to create the given
+// hashed key.
+func (c *crypter) Cost(hashedKey string) (int, error) {
+	setting := []byte(hashedKey)
+	if !bytes.HasPrefix(setting, []byte(MagicPrefix)) {
+		return 0, crypt.ErrSaltPrefix
+	}
+	setting = setting[len(MagicPrefix):]
+	if i := bytes.IndexByte(setting, '$'); i != -1 {
+		setting = setting[:i]
+	}
+	p, err := decodeParams(setting)
+	if err != nil {
+		return 0, err
+	}
+	return int(p.nLog2), nil
+}
+
+func (c *crypter) SetSalt(salt crypt.Salt) { c.Salt = salt }
+
+// SetParams sets the base-2 logarithm of N (as rounds), r (as block size) and p
+// (as parallelism).
+func (c *crypter) SetParams(p crypt.Params) error {
+	nLog2, r, par := c.Salt.RoundsDefault, int64(c.r), int64(c.p)
+	if p.Rounds != 0 {
+		nLog2 = p.Rounds
+	}
+	if p.BlockSize != 0 {
+		r = int64(p.BlockSize)
+	}
+	if p.Parallelism != 0 {
+		par = int64(p.Parallelism)
+	}
+
+	if nLog2 < RoundsMin || nLog2 > RoundsMax ||
+		r < 1 || par < 1 || r*par >= 1<<30 ||
+		exceedsLimits(params{flags: flagRWDefaults, nLog2: uint32(nLog2), r: uint32(r), p: uint32(par)}) {
+		return crypt.ErrSaltRounds
+	}
+
+	c.Salt.RoundsDefault = nLog2
+	c.r, c.p = uint32(r), uint32(par)
+	return nil
+}
+
+func (c *crypter) Params(hashedKey string) (crypt.Params, error) {
+	setting := []byte(hashedKey)
+	if !bytes.HasPrefix(setting, []byte(MagicPrefix)) {
+		return crypt.Params{}, crypt.ErrSaltPrefix
+	}
+	setting = setting[len(MagicPrefix):]
+	if i := bytes.IndexByte(setting, '$'); i != -1 {
+		setting = setting[:i]
+	}
+	p, err := decodeParams(setting)
+	if err != nil {
+		return crypt.Params{}, err
+	}
+	return crypt.Params{
+		Rounds:      int(p.nLog2),
+		BlockSize:   int(p.r),
+		Parallelism: int(p.p),
+	}, nil
+}
+
+// generateSalt returns a random salt with the default parameters, as the ones
+// of libxcrypt for "$y$".
+func (c *crypter) generateSalt() []byte {
+	raw := make([]byte, 16)
+	rand.Read(raw)
+
+	out := make([]byte, 0, len(c.Salt.MagicPrefix)+32)
+	out = append(out, c.Salt.MagicPrefix...)
+	out = append(out, encodeParams(params{
+		flags: flagRWDefaults,
+		nLog2: uint32(c.Salt.RoundsDefault),
+		r:     c.r,
+		p:     c.p,
+	})...)
+	out = append(out, '$')
+	out = append(out, crypt.Base64_24Bit(raw)...)
+	return out
+}
+
+// decodeParams decodes the flavor, N, r and the optional p and t.
+func decodeParams(b []byte) (p params, err error) {
+	var flavor, have uint32
+
+	if b, err = decode64Uint32(&flavor, b, 0); err != nil {
+		return p, err
+	}
+	if flavor < flagRW {
+		p.flags = int(flavor)
+	} else if flavor <= flagRW+(flagRWFlavorMask>>flagRWFlavorShift) {
+		p.flags = flagRW + int(flavor-flagRW)<<flagRWFlavorShift
+	} else {
+		return p, crypt.ErrSaltFormat
+	}
+
+	if b, err = decode64Uint32(&p.nLog2, b, 1); err != nil {
+		return p, err
+	}
+	if p.nLog2 > RoundsMax {
+		return p, crypt.ErrSaltRounds
+	}
+	if b, err = decode64Uint32(&p.r, b, 1); err != nil {
+		return p, err
+	}
+
+	p.p = 1
+	if len(b) != 0 {
+		if b, err = decode64Uint32(&have, b, 1); err != nil {
+			return p, err
+		}
+		if have&^3 != 0 {
+			return p, ErrUnsupported
+		}
+		if have&1 != 0 {
+			if b, err = decode64Uint32(&p.p, b, 2); err != nil {
+				return p, err
+			}
+		}
+		if have&2 != 0 {
+			if b, err = decode64Uint32(&p.t, b, 1); err != nil {
+				return p, err
+			}
+		}
+	}
+	if len(b) != 0 {
+		return p, crypt.ErrSaltFormat
+	}
+	if exceedsLimits(p) {
+		return p, crypt.ErrSaltRounds
+	}
+
+	return p, nil
+}
+
+// exceedsLimits reports whether yescrypt would allocate more than MemoryMax
+// bytes or mix more than WorkMax bytes with the given parameters.
+func exceedsLimits(p params) bool {
+	if p.nLog2 >= 32 {
+		return true
+	}
+	n := uint64(1) << p.nLog2
+	if uint64(p.r) > MemoryMax/128/(n+uint64(p.p)) {
+		return true
+	}
+
+	// Each lane mixes all the blocks without the RW flavor.
+	passes := uint64(max(p.t, 1))
+	if p.flags&flagRW == 0 {
+		passes *= uint64(p.p)
+	}
+	return uint64(p.r)*n > WorkMax/128/passes
+}
+
+func encodeParams(p params) []byte {
+	var flavor uint32
+	if p.flags < flagRW {
+		flavor = uint32(p.flags)
+	} else {
+		flavor = flagRW + uint32(p.flags>>flagRWFlavorShift)
+	}
+
+	out := make([]byte, 0, 16)
+	out = encode64Uint32(out, flavor, 0)
+	out = encode64Uint32(out, p.nLog2, 1)
+	out = encode64Uint32(out, p.r, 1)
+
+	var have uint32
+	if p.p != 1 {
+		have |= 1
+	}
+	if p.t != 0 {
+		have |= 2
+	}
+	if have != 0 {
+		out = encode64Uint32(out, have, 1)
+	}
+	if p.p != 1 {
+		out = encode64Uint32(out, p.p, 2)
+	}
+	if p.t != 0 {
+		out = encode64Uint32(out, p.t, 1)
+	}
+	return out
+}
+
+// decode64Uint32 decodes into dst a variable-length integer, which can not be
+// lower than min, returning the rest of src.
+func decode64Uint32(dst *uint32, src []byte, min uint32) ([]byte, error) {
+	var start, end, chars, bits uint32 = 0, 47, 1, 0
+
+	if len(src) == 0 {
+		return nil, crypt.ErrSaltFormat
+	}
+	c := uint32(bytes.IndexByte([]byte(alphabet), src[0]))
+	if c > 63 {
+		return nil, crypt.ErrSaltFormat
+	}
+	src = src[1:]
+
+	*dst = min
+	for c > end {
+		*dst += (end + 1 - start) << bits
+		start = end + 1
+		end = start + (62-end)/2
+		chars++
+		bits += 6
+	}
+	*dst += (c - start) << bits
+
+	for ; chars > 1; chars-- {
+		if len(src) == 0 {
+			return nil, crypt.ErrSaltFormat
+		}
+		c = uint32(bytes.IndexByte([]byte(alphabet), src[0]))
+		if c > 63 {
+			return nil, crypt.ErrSaltFormat
+		}
+		src = src[1:]
+		bits -= 6
+		*dst += c << bits
+	}
+
+	return src, nil
+}
+
+// encode64Uint32 is the inverse of decode64Uint32.
+func encode64Uint32(dst []byte, src, min uint32) []byte {
+	var start, end, chars, bits uint32 = 0, 47, 1, 0
+
+	src -= min
+	for {
+		count := (end + 1 - start) << bits
+		if src < count {
+			break
+		}
+		start = end + 1
+		end = start + (62-end)/2
+		src -= count
+		chars++
+		bits += 6
+	}
+
+	dst = append(dst, alphabet[start+(src>>bits)])
+	for ; chars > 1; chars-- {
+		bits -= 6
+		dst = append(dst, alphabet[(src>>bits)&0x3f])
+	}
+	return dst
+}
+
+// decode64 decodes the little-endian crypt Base64 of src, whose unused bits
+// have to be zero.
+func decode64(src []byte) ([]byte, error) {
+	dst := make([]byte, 0, len(src)*3/4)
+
+	for len(src) != 0 {
+		var value, bits uint32
+		for len(src) != 0 && bits < 24 {
+			c := uint32(bytes.IndexByte([]byte(alphabet), src[0]))
+			if c > 63 {
+				return nil, crypt.ErrSaltFormat
+			}
+			src = src[1:]
+			value |= c << bits
+			bits += 6
+		}
+		if bits < 12 { // There must be at least one full byte.
+			return nil, crypt.ErrSaltFormat
+		}
+		for ; bits >= 8; bits -= 8 {
+			dst = append(dst, byte(value))
+			value >>= 8
+		}
+		if value != 0 {
+			return nil, crypt.ErrSaltFormat
+		}
+	}
+
+	return dst, nil
+}
@@ -0,0 +1,169 @@
+// Copyright 2026, Trident Project
+// All rights reserved.
+//
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package yescrypt_crypt
+
+import (
+	"testing"
+
+	"trident.li/go/osutil-crypt/common"
+)
+
+var yescryptCrypt = New()
+
+func TestGenerate(t *testing.T) {
+	data := []struct {
+		salt []byte
+		key  []byte
+		out  string
+		cost int
+	}{
+		{
+			[]byte("$y$j9T$abcdefgh$"),
+			[]byte("test"),
+			"$y$j9T$abcdefgh$4aYJlpUYBQ/CwvNaNZWyJ8iEiOSOhXhXiF4pD9oU0p6",
+			12,
+		},
+		{
+			[]byte("$y$j9T$abcdefgh"),
+			[]byte(""),
+			"$y$j9T$abcdefgh$iIo39NptKgz9489QUZiS9KDgwTqSQYTX5vr0wGK8Su5",
+			12,
+		},
+		{
+			[]byte("$y$j75$LdJMENpBABJJ3hIHjB1Bi."),
+			[]byte("password"),
+			"$y$j75$LdJMENpBABJJ3hIHjB1Bi.$AwSWBvo9otG8BLH4EfD1adasacj5dqew9dxGW5j5f24",
+			10,
+		},
+		{
+			[]byte("$y$j75..$LdJMENpBABJJ3hIHjB1Bi."),
+			[]byte("password"),
+			"$y$j75..$LdJMENpBABJJ3hIHjB1Bi.$CNNJTnOCni/dqsaM1xsSPkCaLWFXnSfkZXQuUDGLH70",
+			10,
+		},
+		{
+			[]byte("$y$j75/.$LdJMENpBABJJ3hIHjB1Bi."),
+			[]byte("password"),
+			"$y$j75/.$LdJMENpBABJJ3hIHjB1Bi.$tw6JXovBMSlBPl9h7ekV7rVPXLcdeWgkvyuFsVIaQXB",
+			10,
+		},
+		{
+			[]byte("$y$jD5$LdJMENpBABJJ3hIHjB1Bi."),
+			[]byte("password"),
+			"$y$jD5$LdJMENpBABJJ3hIHjB1Bi.$zutiB.QdPSKpqHos/AoMaTHBbqnwpA2kn5DpQvSl7X6",
+			16,
+		},
+		{
+			[]byte("$y$.75$LdJMENpBABJJ3hIHjB1Bi."),
+			[]byte("password"),
+			"$y$.75$LdJMENpBABJJ3hIHjB1Bi.$MmpV0n2X/95WrtX1WEQxFp.x5AhQ0KY9ReBR4Ac5ziD",
+			10,
+		},
+		{
+			[]byte("$y$/75$LdJMENpBABJJ3hIHjB1Bi."),
+			[]byte("password"),
+			"$y$/75$LdJMENpBABJJ3hIHjB1Bi.$bkMG/Q42G7s4JyPy4OXYgYva8S4/jdmXx2TAtiJvK67",
+			10,
+		},
+		{
+			[]byte("$y$/75/.$LdJMENpBABJJ3hIHjB1Bi."),
+			[]byte("password"),
+			"$y$/75/.$LdJMENpBABJJ3hIHjB1Bi.$JzVskRczy1mWZYxU8kGfJS/.gOIXFswK4kVPFniWZ67",
+			10,
+		},
+	}
+
+	for i, d := range data {
+		hash, err := yescryptCrypt.Generate(d.key, d.salt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hash != d.out {
+			t.Errorf("Test %d failed\nExpected: %s, got: %s", i, d.out, hash)
+		}
+
+		cost, err := yescryptCrypt.Cost(hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cost != d.cost {
+			t.Errorf("Test %d failed\nExpected: %d, got: %d", i, d.cost, cost)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	data := [][]byte{
+		[]byte("password"),
+		[]byte("12345"),
+		[]byte("That's amazing! I've got the same combination on my luggage!"),
+		[]byte("And change the combination on my luggage!"),
+		[]byte("         random  spa  c    ing."),
+		[]byte("94ajflkvjzpe8u3&*j1k513KLJ&*()"),
+	}
+	for i, d := range data {
+		hash, err := yescryptCrypt.Generate(d, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = yescryptCrypt.Verify(hash, d); err != nil {
+			t.Errorf("Test %d failed: %s", i, d)
+		}
+	}
+}
+
+func TestParams(t *testing.T) {
+	c := New()
+	want := crypt.Params{Rounds: 10, BlockSize: 8, Parallelism: 2}
+
+	if err := c.SetParams(crypt.Params{Rounds: RoundsMax + 1}); err != crypt.ErrSaltRounds {
+		t.Errorf("Expected ErrSaltRounds, got: %v", err)
+	}
+	if err := c.SetParams(want); err != nil {
+		t.Fatal(err)
+	}
+
+	hash, err := c.Generate([]byte("password"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params, err := c.Params(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params != want {
+		t.Errorf("Expected: %+v, got: %+v", want, params)
+	}
+	if err = c.Verify(hash, []byte("password")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestVerifyLimits(t *testing.T) {
+	for i, d := range []string{
+		"$y$jST$abcdefgh$",
+		"$y$" + string(encodeParams(params{flags: flagRWDefaults, nLog2: 12, r: 1 << 20, p: 1})) + "$abcdefgh$",
+		"$y$" + string(encodeParams(params{flags: flagRWDefaults, nLog2: 1, r: 1, p: 1<<30 - 1})) + "$abcdefgh$",
+		"$y$" + string(encodeParams(params{flags: flagRWDefaults, nLog2: 18, r: 32, p: 1})) + "$abcdefgh$",
+		"$y$" + string(encodeParams(params{flags: flagRWDefaults, nLog2: 12, r: 32, p: 1, t: 1 << 20})) + "$abcdefgh$",
+		"$y$" + string(encodeParams(params{nLog2: 12, r: 32, p: 1 << 10})) + "$abcdefgh$",
+	} {
+		if err := New().Verify(d, []byte("x")); err != crypt.ErrSaltRounds {
+			t.Errorf("Test %d failed\nExpected: %v, got: %v", i, crypt.ErrSaltRounds, err)
+		}
+	}
+
+	if err := New().SetParams(crypt.Params{Rounds: 20, BlockSize: 1024}); err != crypt.ErrSaltRounds {
+		t.Errorf("Expected ErrSaltRounds, got: %v", err)
+	}
+	if err := New().SetParams(crypt.Params{Rounds: 18, BlockSize: 32}); err != crypt.ErrSaltRounds {
+		t.Errorf("Expected ErrSaltRounds, got: %v", err)
+	}
+	if err := New().SetParams(crypt.Params{Rounds: 17, BlockSize: 32}); err != nil {
+		t.Error(err)
+	}
+}